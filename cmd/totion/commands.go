@@ -0,0 +1,218 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/AbhaySingh002/Totion/internal/config"
+	"github.com/AbhaySingh002/Totion/internal/links"
+	"github.com/AbhaySingh002/Totion/internal/search"
+	"github.com/AbhaySingh002/Totion/internal/store"
+	"github.com/charmbracelet/x/term"
+)
+
+const usage = `Usage: totion [--dir <path>] [command]
+
+Without a command Totion starts the note-taking UI.
+
+Commands:
+  today            Open today's daily note, creating it if needed
+  search <query>   Search note contents ("a phrase", prefix*)
+  graph            Print the links between notes; flags:
+                     --format dot|json  output format (default dot)
+                     --tags             add tags as nodes
+  config           Print the effective settings
+  auth             Show where the Gemini API key comes from
+  auth set         Store the Gemini API key, read from the terminal or stdin
+  auth clear       Remove the stored Gemini API key
+  help             Show this help
+
+Options:
+  --dir <path>     Notes directory. Overrides $TOTION_DIR, which overrides
+                   notes_dir in the config file; the default is ~/.totion
+`
+
+// runCommand runs a subcommand and returns the process exit code.
+func runCommand(opts options, name string, args []string) int {
+	switch name {
+	case "search":
+		return runSearch(opts.cfg, args)
+	case "graph":
+		return runGraph(opts.cfg, args)
+	case "config":
+		return runConfig(opts)
+	case "auth":
+		return runAuth(opts, args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", name, usage)
+		return 2
+	}
+}
+
+func runSearch(cfg config.Config, args []string) int {
+	query := strings.Join(args, " ")
+	if strings.TrimSpace(query) == "" {
+		fmt.Fprintln(os.Stderr, "usage: totion search <query>")
+		return 2
+	}
+	if err := makeNotesDir(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	matches, err := search.Search(context.Background(), store.NewFSStore(cfg.NotesDir), query)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "search failed: %v\n", err)
+		return 1
+	}
+	for _, m := range matches {
+		fmt.Printf("%s:%d: %s\n", m.Path, m.Line, strings.TrimSpace(m.Text))
+	}
+	if len(matches) == 0 {
+		return 1
+	}
+	return 0
+}
+
+// runGraph prints the link graph of the notes for Graphviz or other tools.
+func runGraph(cfg config.Config, args []string) int {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	format := fs.String("format", "dot", "output format: dot or json")
+	tags := fs.Bool("tags", false, "add tags as nodes")
+	fs.Usage = func() { fmt.Fprintln(os.Stderr, "usage: totion graph [--format dot|json] [--tags]") }
+	if err := fs.Parse(args); err != nil || fs.NArg() > 0 {
+		if fs.NArg() > 0 {
+			fs.Usage()
+		}
+		return 2
+	}
+	if *format != "dot" && *format != "json" {
+		fmt.Fprintf(os.Stderr, "unknown format %q; use dot or json\n", *format)
+		return 2
+	}
+	if err := makeNotesDir(cfg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	idx := links.NewIndex()
+	if err := idx.Refresh(context.Background(), store.NewFSStore(cfg.NotesDir)); err != nil {
+		fmt.Fprintf(os.Stderr, "could not read notes: %v\n", err)
+		return 1
+	}
+	g := idx.Graph(*tags)
+	write := g.WriteDOT
+	if *format == "json" {
+		write = g.WriteJSON
+	}
+	if err := write(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "could not print graph: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runConfig prints the settings Totion runs with as TOML, ready to be used
+// as a starting point for the config file.
+func runConfig(opts options) int {
+	status := "not found, using defaults"
+	if _, err := os.Stat(opts.configPath); err == nil {
+		status = "loaded"
+	}
+	fmt.Printf("# config file: %s (%s)\n", opts.configPath, status)
+	fmt.Printf("# notes_dir from: %s\n", opts.dirSource)
+	if err := opts.resolveAPIKey(); err != nil {
+		fmt.Printf("# api key: %v\n\n", err)
+	} else if opts.keySource == "" {
+		fmt.Print("# api key: not set\n\n")
+	} else {
+		fmt.Printf("# api key from: %s\n\n", opts.keySource)
+	}
+	// The key itself is never printed.
+	cfg := opts.cfg
+	cfg.Autocomplete.APIKey = ""
+	if err := cfg.Write(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "could not print config: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runAuth shows, stores or removes the Gemini API key kept in the
+// credentials file.
+func runAuth(opts options, args []string) int {
+	path, err := config.CredentialsPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "could not locate credentials file: %v\n", err)
+		return 1
+	}
+	sub := ""
+	if len(args) > 0 {
+		sub = args[0]
+	}
+	switch sub {
+	case "", "status":
+		if err := opts.resolveAPIKey(); err != nil {
+			fmt.Fprintf(os.Stderr, "could not read API key: %v\n", err)
+			return 1
+		}
+		if opts.keySource == "" {
+			fmt.Printf("No Gemini API key set. Set %s or run `totion auth set`.\n", config.EnvAPIKey)
+			return 1
+		}
+		fmt.Printf("Gemini API key %s from %s\n", config.MaskKey(opts.cfg.Autocomplete.APIKey), opts.keySource)
+		return 0
+	case "set":
+		key, err := readKey()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "could not read API key: %v\n", err)
+			return 1
+		}
+		if err := config.WriteCredentials(path, key); err != nil {
+			fmt.Fprintf(os.Stderr, "could not store API key: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Stored the API key in %s\n", path)
+		if os.Getenv(config.EnvAPIKey) != "" {
+			fmt.Printf("Note: %s is set and takes precedence.\n", config.EnvAPIKey)
+		}
+		return 0
+	case "clear":
+		if err := config.ClearCredentials(path); err != nil {
+			fmt.Fprintf(os.Stderr, "could not remove API key: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Removed %s\n", path)
+		if err := opts.resolveAPIKey(); err == nil && opts.keySource != "" {
+			fmt.Printf("Note: a key is still set through %s.\n", opts.keySource)
+		}
+		return 0
+	default:
+		fmt.Fprintln(os.Stderr, "usage: totion auth [set|clear]")
+		return 2
+	}
+}
+
+// readKey prompts for the API key without echoing it when stdin is a
+// terminal, and otherwise reads the first line of stdin so the key can be
+// piped in. It is never taken from the command line, where it would end up
+// in the shell history.
+func readKey() (string, error) {
+	fd := os.Stdin.Fd()
+	if term.IsTerminal(fd) {
+		fmt.Fprint(os.Stderr, "Gemini API key: ")
+		key, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		return string(key), err
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return line, nil
+}
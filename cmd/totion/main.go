@@ -1,23 +1,112 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/AbhaySingh002/Totion/internal/app"
+	"github.com/AbhaySingh002/Totion/internal/config"
+	"github.com/AbhaySingh002/Totion/internal/styles"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 func main() {
-	if err := os.MkdirAll(app.NotesDir, 0750); err != nil {
-		fmt.Printf("could not create notes directory: %v", err)
+	dir := flag.String("dir", "", "notes directory")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	opts, err := loadConfig(*dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	// `totion today` starts the UI like no command at all, with the daily
+	// note open.
+	today := flag.Arg(0) == "today"
+	if today && flag.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "usage: totion today")
+		os.Exit(2)
+	}
+	if flag.NArg() > 0 && !today {
+		os.Exit(runCommand(opts, flag.Arg(0), flag.Args()[1:]))
+	}
+	if err := makeNotesDir(opts.cfg); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
 
-	p := tea.NewProgram(app.InitialModel())
+	if err := opts.resolveAPIKey(); err != nil {
+		fmt.Fprintf(os.Stderr, "could not read API key: %v\n", err)
+		os.Exit(1)
+	}
+	theme, err := styles.Resolve(opts.cfg.Theme, opts.cfg.Themes, lipgloss.HasDarkBackground, os.Getenv("NO_COLOR") != "")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	styles.Apply(theme)
+	model := app.InitialModel(opts.cfg)
+	if today {
+		if err := model.OpenJournal(time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "could not open today's note: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	p := tea.NewProgram(model)
 
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Alas, there's been an error: %v", err)
 		os.Exit(1)
 	}
-}
\ No newline at end of file
+}
+
+// options are the effective settings along with where they came from.
+type options struct {
+	cfg        config.Config
+	configPath string
+	dirSource  string
+	keySource  string
+}
+
+// loadConfig reads the config file and settles the notes directory, with
+// the --dir flag taking precedence over TOTION_DIR and the config file.
+func loadConfig(dirFlag string) (options, error) {
+	path, err := config.Path()
+	if err != nil {
+		return options{}, fmt.Errorf("could not locate config file: %w", err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return options{}, err
+	}
+	opts := options{cfg: cfg, configPath: path}
+	opts.cfg.NotesDir, opts.dirSource, err = config.ResolveNotesDir(dirFlag, os.Getenv(config.EnvDir), cfg)
+	if err != nil {
+		return options{}, fmt.Errorf("could not resolve notes directory: %w", err)
+	}
+	return opts, nil
+}
+
+// makeNotesDir creates the notes directory. Only the commands that read or
+// write notes call it, so a mistyped --dir is not created by `totion config`.
+func makeNotesDir(cfg config.Config) error {
+	if err := os.MkdirAll(cfg.NotesDir, 0750); err != nil {
+		return fmt.Errorf("could not create notes directory: %w", err)
+	}
+	return nil
+}
+
+// resolveAPIKey settles the Gemini API key, with GEMINI_API_KEY taking
+// precedence over the config file and the credentials file.
+func (o *options) resolveAPIKey() error {
+	path, err := config.CredentialsPath()
+	if err != nil {
+		return err
+	}
+	o.cfg.Autocomplete.APIKey, o.keySource, err = config.ResolveAPIKey(os.Getenv(config.EnvAPIKey), o.cfg, path)
+	return err
+}
@@ -3,62 +3,168 @@ package app
 // models of BubbleTea
 import (
 	"context"
+	"errors"
 	"fmt"
-	"io"
-	"log"
-	"os"
+	"io/fs"
 	"strings"
 	"time"
 
+	"github.com/AbhaySingh002/Totion/internal/config"
 	"github.com/AbhaySingh002/Totion/internal/file"
+	"github.com/AbhaySingh002/Totion/internal/history"
+	"github.com/AbhaySingh002/Totion/internal/keymap"
+	"github.com/AbhaySingh002/Totion/internal/links"
+	"github.com/AbhaySingh002/Totion/internal/lock"
+	"github.com/AbhaySingh002/Totion/internal/markdown"
+	"github.com/AbhaySingh002/Totion/internal/search"
+	"github.com/AbhaySingh002/Totion/internal/store"
 	"github.com/AbhaySingh002/Totion/internal/styles"
+	"github.com/AbhaySingh002/Totion/internal/trash"
 	"github.com/AbhaySingh002/Totion/internal/tui"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"google.golang.org/genai"
 )
 
-var NotesDir string
-
-func init() {
-	homedir, err := os.UserHomeDir()
-	if err != nil {
-		log.Fatal("Error getting home dir", err)
-	}
-	NotesDir = fmt.Sprintf("%s/.totion", homedir)
+// tickMsg drives autocomplete and autosave while a note is open. gen ties a
+// tick to the note it was started for so stale loops die out on reopen.
+type tickMsg struct {
+	gen  int
+	time time.Time
 }
 
-type tickMsg struct{}
-
-func tickCmd() tea.Cmd {
+func tickCmd(gen int) tea.Cmd {
 	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
-		return tickMsg{}
+		return tickMsg{gen: gen, time: t}
 	})
 }
 
+// InputMode selects what the name prompt creates.
+type InputMode int
+
+const (
+	InputNote InputMode = iota
+	InputFolder
+	InputRename
+)
+
 type Model struct {
+	Config                 	config.Config
+	Keys                   	keymap.KeyMap
 	NewFileInput          	textinput.Model
 	CreateFileInputVisible	bool
-	CurrentNote            	*os.File
+	InputMode              	InputMode
+	CurrentDir             	string
+	RenamePath             	string
+	RenameTitle            	string
+	DiskInfo               	store.Info
+	DiskContent            	string
+	Conflict               	bool
+	ListStamp              	string
+	Locker                 	*lock.Locker
+	LockOwner              	*lock.Owner
+	LockedAt               	time.Time
+	ReadOnly               	bool
+	CurrentNote            	*file.Note
+	Store                  	store.NoteStore
 	NoteContent            	textarea.Model
 	List                   	list.Model
 	ListVisible            	bool
+	Tags                   	list.Model
+	TagsVisible            	bool
+	TagFilter              	string
+	JournalFilter          	bool
+	SearchInput            	textinput.Model
+	SearchResults          	list.Model
+	SearchVisible          	bool
+	SearchSeq              	int
+	Index                  	*search.Index
+	History                	*history.History
+	HistoryVisible         	bool
+	HistoryList            	list.Model
+	HistoryDiff            	viewport.Model
+	Links                  	*links.Index
+	BacklinksVisible       	bool
+	BacklinksList          	list.Model
+	GraphVisible           	bool
+	GraphView              	viewport.Model
+	Layout                 	Layout
+	PreviewGen             	int
+	LinkBack               	[]string
+	LinkForward            	[]string
+	PreviewView            	viewport.Model
+	Markdown               	markdown.Renderer
+	Trash                  	*trash.Trash
+	TrashVisible           	bool
+	TrashList              	list.Model
+	PurgePending           	bool
 	ErrMsg                 	string
 	Ctx                    	context.Context
 	Client                 	*genai.Client
+	AIDisabled             	string // why Client is nil
 	Suggestion             	string
 	AutoCompleteEnabled    	bool
 	Width                  	int
 	Height                 	int
 	SuggesTimeCount        	int
 	PrevNoteLength         	int
+	QuitPending            	bool
+	TickGen                	int
+	Dirty                  	bool
+	LastEdit               	time.Time
+	LastSaved              	time.Time
+	AutoSaveIdle           	time.Duration
+	AutoSaveInterval       	time.Duration
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.EnableMouseCellMotion
+	if m.CurrentNote != nil {
+		// Opened from the command line, e.g. by `totion today`.
+		return tea.Batch(tea.EnableMouseCellMotion, watchCmd(), tickCmd(m.TickGen))
+	}
+	return tea.Batch(tea.EnableMouseCellMotion, watchCmd())
+}
+
+// searchItem shows a search match in a list, with the match highlighted.
+type searchItem struct{ search.Match }
+
+func (i searchItem) Description() string {
+	before, match, after := search.Snippet(i.Match, 70)
+	if match == "" {
+		return before
+	}
+	return before + styles.HighlightStyle.Render(match) + after
+}
+
+func (i searchItem) FilterValue() string { return i.Path }
+
+// searchResultMsg carries the outcome of a background search. seq matches
+// SearchSeq at the time the search started so outdated results are dropped.
+type searchResultMsg struct {
+	seq     int
+	matches []search.Match
+	err     error
+}
+
+func (m *Model) searchCmd() tea.Cmd {
+	m.SearchSeq++
+	seq, query, noteStore, idx, ctx := m.SearchSeq, m.SearchInput.Value(), m.Store, m.Index, m.Ctx
+	return func() tea.Msg {
+		if err := idx.Refresh(ctx, noteStore); err != nil {
+			return searchResultMsg{seq: seq, err: err}
+		}
+		matches, err := idx.Search(ctx, noteStore, query)
+		if err == nil {
+			// The index is only a cache; a failed save is retried later.
+			idx.Save(noteStore)
+		}
+		return searchResultMsg{seq: seq, matches: matches, err: err}
+	}
 }
 
 type suggestionMsg struct {
@@ -69,11 +175,11 @@ type suggestionMsg struct {
 func (m *Model) generateSuggestionCmd() tea.Cmd {
 	return func() tea.Msg {
 		if m.Client == nil {
-			return suggestionMsg{"", fmt.Errorf("AI client not available")}
+			return suggestionMsg{"", fmt.Errorf("AI client not available: %s", m.AIDisabled)}
 		}
-		temp := float32(0.9)
+		temp := float32(m.Config.Autocomplete.Temperature)
 		prompt := fmt.Sprintf(SystemPrompt, m.NoteContent.Value())
-		resp, err := m.Client.Models.GenerateContent(m.Ctx, GenaiModel, genai.Text(prompt), &genai.GenerateContentConfig{Temperature: &temp})
+		resp, err := m.Client.Models.GenerateContent(m.Ctx, m.Config.Autocomplete.Model, genai.Text(prompt), &genai.GenerateContentConfig{Temperature: &temp})
 		if err != nil {
 			return suggestionMsg{"", err}
 		}
@@ -86,76 +192,195 @@ func (m *Model) generateSuggestionCmd() tea.Cmd {
 	}
 }
 
-func (m *Model) OpenOrCreateFile(filePath string) error {
-	var f *os.File
-	var err error
-	_, statErr := os.Stat(filePath)
-	if statErr == nil {
-		f, err = os.OpenFile(filePath, os.O_RDWR, 0644)
-	} else if os.IsNotExist(statErr) {
-		f, err = os.Create(filePath)
-	} else {
+func (m *Model) OpenOrCreateFile(name string) error {
+	reopen := m.CurrentNote != nil && m.CurrentNote.Path() == name
+	info, statErr := m.Store.Stat(name)
+	if errors.Is(statErr, fs.ErrNotExist) {
+		if err := file.ValidPath(name); err != nil {
+			return err
+		}
+		if err := m.Store.Write(name, []byte(file.NewNoteContent("", time.Now()))); err != nil {
+			return err
+		}
+		info, statErr = m.Store.Stat(name)
+	}
+	if statErr != nil {
 		return statErr
 	}
+	content, err := m.Store.Read(name)
 	if err != nil {
 		return err
 	}
-	content, err := io.ReadAll(f)
-	if err != nil {
-		f.Close()
-		return err
+	if !reopen {
+		m.unlockNote()
 	}
-	if _, err := f.Seek(0, 0); err != nil {
-		f.Close()
-		return err
+	note := file.ParseNote(name, info.ModTime, content)
+	m.CurrentNote = &note
+	m.loaded(info, content)
+	m.Conflict = false
+	if !reopen {
+		m.lockNote()
+		if m.Layout == LayoutPreview {
+			m.Layout = LayoutEditor
+			m.resizePanes()
+		}
 	}
-	m.CurrentNote = f
 	m.NoteContent.SetValue(string(content))
+	if m.Layout != LayoutEditor {
+		m.renderPreview()
+	}
+	if m.Layout == LayoutSplit {
+		m.syncPreview()
+	}
 	m.SuggesTimeCount = 0
 	m.PrevNoteLength = len(m.NoteContent.Value())
+	m.Dirty = false
+	m.LastSaved = time.Time{}
+	m.TickGen++
 	m.ErrMsg = ""
 	return nil
 }
 
-func (m *Model) SaveNote() {
+// CreateNote opens the note titled title inside dir, creating it when it
+// does not exist yet. The file name is derived from the title by
+// file.NotePath; when the title does not survive as a file name it is kept
+// in the front matter of the new note instead.
+func (m *Model) CreateNote(dir, title string) error {
+	name, keepTitle, err := file.NotePath(dir, title)
+	if err != nil {
+		return err
+	}
+	if keepTitle != "" {
+		exists, err := store.Exists(m.Store, name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if err := m.Store.Write(name, []byte(file.NewNoteContent(keepTitle, time.Now()))); err != nil {
+				return err
+			}
+		}
+	}
+	return m.OpenOrCreateFile(name)
+}
+
+// WriteNote writes the open note to the store and keeps it open.
+func (m *Model) WriteNote() error {
 	if m.CurrentNote == nil {
-		return
+		return nil
 	}
-	if err := m.CurrentNote.Truncate(0); err != nil {
-		m.ErrMsg = fmt.Sprintf("Truncate error: %v", err)
-		return
+	if m.ReadOnly {
+		if !m.Dirty {
+			return nil
+		}
+		m.ErrMsg = fmt.Sprintf("Save error: %v • %s saves your edits as a copy", errReadOnlyEdits, m.Keys.Save.Help().Key)
+		return errReadOnlyEdits
 	}
-	if _, err := m.CurrentNote.Seek(0, 0); err != nil {
-		m.ErrMsg = fmt.Sprintf("Seek error: %v", err)
-		return
+	if err := m.checkLock(true); err != nil {
+		m.ErrMsg = fmt.Sprintf("Save error: %v", err)
+		return err
 	}
-	if _, err := m.CurrentNote.WriteString(m.NoteContent.Value()); err != nil {
-		m.ErrMsg = fmt.Sprintf("Write error: %v", err)
-		return
+	if changed, err := m.changedOnDisk(); err == nil && changed {
+		m.Conflict = true
+		m.ErrMsg = fmt.Sprintf("Save error: %v", errConflict)
+		return errConflict
 	}
-	if err := m.CurrentNote.Close(); err != nil {
-		m.ErrMsg = fmt.Sprintf("Close error: %v", err)
-		return
+	content := []byte(m.NoteContent.Value())
+	if err := m.Store.Write(m.CurrentNote.Path(), content); err != nil {
+		m.ErrMsg = fmt.Sprintf("Save error: %v", err)
+		return err
+	}
+	if info, err := m.Store.Stat(m.CurrentNote.Path()); err == nil {
+		m.loaded(info, content)
+		if m.Index != nil {
+			m.Index.Update(m.CurrentNote.Path(), content, info)
+		}
+		if m.Links != nil {
+			m.Links.Update(m.CurrentNote.Path(), content, info)
+		}
 	}
+	m.Dirty = false
+	m.LastSaved = time.Now()
+	m.ErrMsg = ""
+	// The note itself is safe at this point, so a failed snapshot is only
+	// reported.
+	if m.History != nil {
+		if err := m.History.Record(m.CurrentNote.Path(), content); err != nil {
+			m.ErrMsg = fmt.Sprintf("History error: %v", err)
+		}
+	}
+	return nil
+}
+
+// SaveNote writes the open note and closes it. On failure the note stays
+// open with its content so nothing typed is lost, and the error is shown.
+func (m *Model) SaveNote() error {
+	if m.CurrentNote == nil {
+		return nil
+	}
+	if err := m.WriteNote(); err != nil {
+		return err
+	}
+	if m.Index != nil {
+		m.Index.Save(m.Store)
+	}
+	m.unlockNote()
 	m.CurrentNote = nil
+	m.ReadOnly = false
+	m.LinkBack, m.LinkForward = nil, nil
 	m.NoteContent.SetValue("")
 	m.ErrMsg = ""
+	return nil
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tickMsg:
-		if m.CurrentNote == nil || !m.AutoCompleteEnabled {
+		if m.CurrentNote == nil || msg.gen != m.TickGen {
 			return m, nil
 		}
-		m.SuggesTimeCount++
 		var cmds []tea.Cmd
-		if m.SuggesTimeCount == 3 {
-			cmds = append(cmds, m.generateSuggestionCmd())
+		if m.AutoCompleteEnabled && m.Layout != LayoutPreview {
+			m.SuggesTimeCount++
+			if m.SuggesTimeCount == m.Config.Autocomplete.Delay {
+				cmds = append(cmds, m.generateSuggestionCmd())
+			}
 		}
-		cmds = append(cmds, tickCmd())
+		if m.autoSaveDue(msg.time) {
+			m.WriteNote()
+		}
+		cmds = append(cmds, tickCmd(m.TickGen))
 		return m, tea.Batch(cmds...)
+	case watchMsg:
+		gen := m.TickGen
+		m.checkDisk()
+		if m.TickGen != gen && m.CurrentNote != nil {
+			// Reloading the note stopped its tick loop; start a new one.
+			return m, tea.Batch(watchCmd(), tickCmd(m.TickGen))
+		}
+		return m, watchCmd()
+	case searchResultMsg:
+		if msg.seq != m.SearchSeq {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.ErrMsg = fmt.Sprintf("Search error: %v", msg.err)
+			return m, nil
+		}
+		items := make([]list.Item, len(msg.matches))
+		for i, match := range msg.matches {
+			items[i] = searchItem{match}
+		}
+		m.SearchResults.Title = fmt.Sprintf("Search results (%d)", len(items))
+		m.SearchResults.Select(0)
+		return m, m.SearchResults.SetItems(items)
+	case previewMsg:
+		if msg.gen == m.PreviewGen && m.Layout == LayoutSplit && m.CurrentNote != nil {
+			m.renderPreview()
+			m.syncPreview()
+		}
+		return m, nil
 	case suggestionMsg:
 		if msg.err != nil {
 			m.ErrMsg = fmt.Sprintf("Suggestion error: %v", msg.err)
@@ -172,15 +397,46 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		contentWidth := msg.Width - h
 		contentHeight := msg.Height - v - 10
 		m.List.SetSize(contentWidth, contentHeight)
-		m.NoteContent.SetWidth(contentWidth)
-		m.NoteContent.SetHeight(contentHeight)
+		m.Tags.SetSize(contentWidth, contentHeight)
+		m.SearchResults.SetSize(contentWidth, contentHeight-2)
+		m.SearchInput.Width = contentWidth
+		m.resizeHistory(contentWidth, contentHeight)
+		m.TrashList.SetSize(contentWidth, contentHeight)
+		m.BacklinksList.SetSize(contentWidth, contentHeight)
+		m.GraphView.Width = contentWidth
+		m.GraphView.Height = contentHeight - 2
+		m.resizePanes()
 		m.NewFileInput.Width = contentWidth
 		return m, nil
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+t":
+		if m.Conflict && !key.Matches(msg, m.Keys.Quit) {
+			return m.updateConflict(msg)
+		}
+		if m.LockOwner != nil && m.CurrentNote != nil && !key.Matches(msg, m.Keys.Quit) {
+			return m.updateLockPrompt(msg)
+		}
+		if m.HistoryVisible && !key.Matches(msg, m.Keys.Quit) {
+			return m.updateHistory(msg)
+		}
+		if m.BacklinksVisible && !key.Matches(msg, m.Keys.Quit) {
+			return m.updateBacklinks(msg)
+		}
+		if m.GraphVisible && !key.Matches(msg, m.Keys.Quit) {
+			return m.updateGraph(msg)
+		}
+		if m.TrashVisible && !key.Matches(msg, m.Keys.Quit) {
+			return m.updateTrash(msg)
+		}
+		if m.Layout == LayoutPreview && m.CurrentNote != nil && !key.Matches(msg, m.Keys.Quit) {
+			return m.updatePreview(msg)
+		}
+		switch {
+		case key.Matches(msg, m.Keys.ToggleAutocomplete):
+			if m.CurrentNote != nil && m.Client == nil {
+				m.ErrMsg = "AI is disabled: " + m.AIDisabled
+				return m, nil
+			}
 			if m.CurrentNote != nil {
-				oldEnabled := m.AutoCompleteEnabled
 				m.AutoCompleteEnabled = !m.AutoCompleteEnabled
 				if !m.AutoCompleteEnabled {
 					m.Suggestion = ""
@@ -189,143 +445,484 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.PrevNoteLength = len(m.NoteContent.Value())
 				}
 				m.ErrMsg = fmt.Sprintf("Autocomplete %s", map[bool]string{true: "enabled", false: "disabled"}[m.AutoCompleteEnabled])
-				if m.AutoCompleteEnabled && !oldEnabled {
-					return m, tickCmd()
-				}
 				return m, nil
 			}
-		case "tab":
-			if m.CurrentNote != nil && m.AutoCompleteEnabled && m.Suggestion != "" {
+		case key.Matches(msg, m.Keys.AcceptSuggestion):
+			if m.CurrentNote != nil && !m.ReadOnly && m.AutoCompleteEnabled && m.Suggestion != "" {
 				current := m.NoteContent.Value()
 				m.NoteContent.SetValue(current + " " + m.Suggestion)
 				m.Suggestion = ""
+				m.markDirty()
 				return m, nil
 			}
-		case "ctrl+l":
+		case key.Matches(msg, m.Keys.List):
 			if !m.ListVisible {
+				if err := m.SaveNote(); err != nil {
+					return m, nil
+				}
 				m.ListVisible = true
 				m.CreateFileInputVisible = false
-				if m.CurrentNote != nil {
-					m.SaveNote()
+				m.TagsVisible = false
+				m.SearchVisible = false
+				m.TagFilter = ""
+				m.JournalFilter = false
+				m.ErrMsg = ""
+				m.refreshList()
+				return m, nil
+			}
+		case key.Matches(msg, m.Keys.Journal):
+			if m.ListVisible && m.List.FilterState() != list.Filtering {
+				m.TagFilter = ""
+				m.JournalFilter = true
+				m.List.ResetFilter()
+				m.ErrMsg = ""
+				m.refreshList()
+				m.List.Select(0)
+				return m, nil
+			}
+		case key.Matches(msg, m.Keys.Today):
+			return m.updateJournal(msg)
+		case key.Matches(msg, m.Keys.PrevDay, m.Keys.NextDay):
+			// Elsewhere the keys are left to the screen, to be typed.
+			if m.isJournal() && !m.CreateFileInputVisible {
+				return m.updateJournal(msg)
+			}
+		case key.Matches(msg, m.Keys.Tags):
+			if m.ListVisible && m.List.FilterState() != list.Filtering {
+				notes, err := file.AllNotes(m.Store)
+				if err != nil {
+					m.ErrMsg = fmt.Sprintf("Error reading tags: %v", err)
+					return m, nil
 				}
-				m.List.SetItems(file.NotesFiles(NotesDir))
+				m.Tags.ResetFilter()
+				m.Tags.SetItems(file.BuildTagIndex(notes).Items())
+				m.ListVisible = false
+				m.TagsVisible = true
 				m.ErrMsg = ""
 				return m, nil
 			}
-		case "delete", "backspace":
-			if m.ListVisible {
-				item, ok := m.List.SelectedItem().(file.Note)
-				if ok {
-					filePath := fmt.Sprintf("%s/%s.md", NotesDir, item.Title())
-					if err := os.Remove(filePath); err != nil {
-						m.ErrMsg = fmt.Sprintf("Error deleting file: %v", err)
+		case key.Matches(msg, m.Keys.Search):
+			if !m.SearchVisible {
+				if err := m.SaveNote(); err != nil {
+					return m, nil
+				}
+				m.ListVisible = false
+				m.TagsVisible = false
+				m.CreateFileInputVisible = false
+				m.SearchVisible = true
+				m.ErrMsg = ""
+				return m, m.SearchInput.Focus()
+			}
+		case key.Matches(msg, m.Keys.NewFolder):
+			if m.ListVisible && m.List.FilterState() != list.Filtering {
+				m.ListVisible = false
+				m.CreateFileInputVisible = true
+				m.InputMode = InputFolder
+				m.ErrMsg = ""
+				return m, nil
+			}
+		case key.Matches(msg, m.Keys.Rename):
+			if m.ListVisible && m.List.FilterState() != list.Filtering {
+				if note, ok := m.List.SelectedItem().(file.Note); ok {
+					m.startRename(note)
+				} else {
+					m.ErrMsg = "Select a note to rename."
+				}
+				return m, nil
+			}
+		case key.Matches(msg, m.Keys.Trash):
+			if m.ListVisible && m.List.FilterState() != list.Filtering {
+				m.openTrash()
+				return m, nil
+			}
+		case key.Matches(msg, m.Keys.Delete):
+			// While filtering the keys edit the filter text instead.
+			if m.ListVisible && m.List.FilterState() != list.Filtering {
+				switch item := m.List.SelectedItem().(type) {
+				case file.Note:
+					m.trashNote(item.Path())
+				case file.Folder:
+					if item.Parent {
+						break
+					}
+					if err := m.Store.Delete(item.Path()); err != nil {
+						m.ErrMsg = fmt.Sprintf("Error deleting folder (only empty folders can be deleted): %v", err)
 					} else {
 						m.ErrMsg = ""
-						m.List.SetItems(file.NotesFiles(NotesDir))
+						m.refreshList()
 					}
-				} else {
+				default:
 					m.ErrMsg = "No item selected. Use arrow keys to select a note."
 				}
 				return m, nil
 			}
-		case "esc":
+		case key.Matches(msg, m.Keys.Back):
+			if m.SearchVisible {
+				m.SearchVisible = false
+				m.ErrMsg = ""
+				return m, nil
+			}
+			if m.TagsVisible {
+				if m.Tags.FilterState() == list.Filtering {
+					break
+				}
+				m.TagsVisible = false
+				m.ListVisible = true
+				return m, nil
+			}
 			if m.CreateFileInputVisible {
 				m.CreateFileInputVisible = false
 				m.ErrMsg = ""
+				if m.InputMode == InputRename {
+					m.NewFileInput.SetValue("")
+					m.ListVisible = true
+					return m, nil
+				}
 			}
-			if m.CurrentNote != nil {
-				m.SaveNote()
-				m.CurrentNote = nil
-				m.ErrMsg = ""
+			if err := m.SaveNote(); err != nil {
+				return m, nil
 			}
 			if m.ListVisible {
 				if m.List.FilterState() == list.Filtering {
 					break
 				}
+				if (m.TagFilter != "" || m.JournalFilter) && m.List.FilterState() == list.Unfiltered {
+					m.TagFilter = ""
+					m.JournalFilter = false
+					m.refreshList()
+					return m, nil
+				}
+				if m.CurrentDir != "" && m.List.FilterState() == list.Unfiltered {
+					m.CurrentDir = file.ParentDir(m.CurrentDir)
+					m.refreshList()
+					return m, nil
+				}
 				m.ListVisible = false
 				m.ErrMsg = ""
 			}
 			return m, nil
-		case "ctrl+c":
-			if m.CurrentNote != nil {
-				m.SaveNote()
+		case key.Matches(msg, m.Keys.Quit):
+			if m.CurrentNote != nil && !m.QuitPending {
+				if err := m.SaveNote(); err != nil {
+					m.QuitPending = true
+					m.ErrMsg += " • " + m.Keys.Quit.Help().Key + " again to quit without saving"
+					return m, nil
+				}
 			}
 			return m, tea.Quit
-		case "ctrl+n":
-			if m.CurrentNote != nil {
-				m.SaveNote()
+		case key.Matches(msg, m.Keys.NewNote):
+			if err := m.SaveNote(); err != nil {
+				return m, nil
 			}
 			m.ListVisible = false
+			m.TagsVisible = false
+			m.SearchVisible = false
+			if m.InputMode == InputRename {
+				m.NewFileInput.SetValue("")
+			}
 			m.CreateFileInputVisible = true
+			m.InputMode = InputNote
 			m.ErrMsg = ""
 			return m, nil
-		case "enter":
+		case key.Matches(msg, m.Keys.Open):
 			if m.CurrentNote != nil {
 				break
 			}
+			if m.SearchVisible {
+				match, ok := m.SearchResults.SelectedItem().(searchItem)
+				if !ok {
+					return m, nil
+				}
+				if err := m.OpenOrCreateFile(match.Path); err != nil {
+					m.ErrMsg = fmt.Sprintf("Error opening file: %v", err)
+					return m, nil
+				}
+				m.gotoLine(match.Line - 1)
+				m.SearchVisible = false
+				return m, tickCmd(m.TickGen)
+			}
+			if m.TagsVisible {
+				if tag, ok := m.Tags.SelectedItem().(file.Tag); ok {
+					m.TagFilter = tag.Name
+					m.JournalFilter = false
+					m.TagsVisible = false
+					m.ListVisible = true
+					m.List.ResetFilter()
+					m.refreshList()
+				}
+				return m, nil
+			}
 			if m.ListVisible {
-				item, ok := m.List.SelectedItem().(file.Note)
-				if ok {
-					filePath := fmt.Sprintf("%s/%s.md", NotesDir, item.Title())
-					if err := m.OpenOrCreateFile(filePath); err != nil {
+				switch item := m.List.SelectedItem().(type) {
+				case file.Note:
+					if err := m.OpenOrCreateFile(item.Path()); err != nil {
 						m.ErrMsg = fmt.Sprintf("Error opening file: %v", err)
 					} else {
 						m.ListVisible = false
-						var initCmd tea.Cmd
-						if m.AutoCompleteEnabled {
-							initCmd = tickCmd()
-						}
-						return m, initCmd
+						return m, tickCmd(m.TickGen)
 					}
-				} else {
+				case file.Folder:
+					m.CurrentDir = item.Path()
+					m.List.ResetFilter()
+					m.refreshList()
+				default:
 					m.ErrMsg = "No item selected. Use arrow keys to select a note."
 				}
 				return m, nil
 			}
 			fileName := strings.TrimSpace(m.NewFileInput.Value())
-			if fileName != "" {
-				filePath := fmt.Sprintf("%s/%s.md", NotesDir, fileName)
-				if err := m.OpenOrCreateFile(filePath); err != nil {
+			if fileName != "" && m.InputMode == InputRename {
+				newPath, keepTitle, err := file.NotePath(file.ParentDir(m.RenamePath), fileName)
+				if err != nil {
+					m.ErrMsg = fmt.Sprintf("Error renaming note: %v", err)
+					return m, nil
+				}
+				if m.RenameTitle != "" {
+					keepTitle = m.RenameTitle
+				}
+				updated, err := m.renameNote(m.RenamePath, newPath, keepTitle)
+				if err != nil {
+					m.ErrMsg = fmt.Sprintf("Error renaming note: %v", err)
+					if updated == 0 {
+						return m, nil
+					}
+				} else {
+					m.ErrMsg = fmt.Sprintf("Renamed to %s • links updated in %d notes", newPath, updated)
+				}
+				m.CreateFileInputVisible = false
+				m.NewFileInput.SetValue("")
+				m.ListVisible = true
+				m.refreshList()
+			} else if fileName != "" && m.InputMode == InputFolder {
+				folder, err := file.FolderPath(m.CurrentDir, fileName)
+				if err == nil {
+					err = m.Store.MakeDir(folder)
+				}
+				if err != nil {
+					m.ErrMsg = fmt.Sprintf("Error creating folder: %v", err)
+				} else {
+					m.CreateFileInputVisible = false
+					m.NewFileInput.SetValue("")
+					m.ListVisible = true
+					m.ErrMsg = ""
+					m.refreshList()
+				}
+			} else if fileName != "" {
+				if err := m.CreateNote(m.CurrentDir, fileName); err != nil {
 					m.ErrMsg = fmt.Sprintf("Error creating/opening file: %v", err)
 				} else {
 					m.CreateFileInputVisible = false
 					m.NewFileInput.SetValue("")
-					var initCmd tea.Cmd
-					if m.AutoCompleteEnabled {
-						initCmd = tickCmd()
-					}
-					return m, initCmd
+					return m, tickCmd(m.TickGen)
 				}
 			}
 			return m, nil
-		case "ctrl+g":
+		case key.Matches(msg, m.Keys.Preview):
+			if m.CurrentNote != nil {
+				m.cycleLayout()
+				return m, nil
+			}
+		case key.Matches(msg, m.Keys.FollowLink, m.Keys.LinkBack, m.Keys.LinkForward):
+			if m.CurrentNote != nil {
+				return m.navigate(msg)
+			}
+		case key.Matches(msg, m.Keys.Backlinks):
+			if m.CurrentNote != nil {
+				m.openBacklinks()
+				return m, nil
+			}
+		case key.Matches(msg, m.Keys.Graph):
+			if m.CurrentNote != nil {
+				m.openGraph()
+				return m, nil
+			}
+		case key.Matches(msg, m.Keys.History):
+			if m.CurrentNote != nil {
+				m.openHistory()
+				return m, nil
+			}
+		case key.Matches(msg, m.Keys.NextSuggestion):
 			if m.CurrentNote != nil && m.AutoCompleteEnabled {
 				return m, m.generateSuggestionCmd()
 			}
-		case "ctrl+s":
-			m.SaveNote()
+		case key.Matches(msg, m.Keys.Save):
+			if m.CurrentNote != nil && m.ReadOnly && m.Dirty {
+				name, err := m.saveCopy()
+				if err != nil {
+					m.ErrMsg = fmt.Sprintf("Save error: %v", err)
+				} else {
+					m.ErrMsg = "Saved your edits as " + name
+				}
+				return m, nil
+			}
+			m.WriteNote()
 			return m, nil
 		}
 	}
+	if _, ok := msg.(tea.KeyMsg); ok {
+		m.QuitPending = false
+	}
 	if m.ListVisible {
 		m.List, cmd = m.List.Update(msg)
 		return m, cmd
 	}
+	if m.TagsVisible {
+		m.Tags, cmd = m.Tags.Update(msg)
+		return m, cmd
+	}
+	if m.SearchVisible {
+		return m.updateSearch(msg)
+	}
 	if m.CreateFileInputVisible {
 		m.NewFileInput, cmd = m.NewFileInput.Update(msg)
 	}
+	if m.Layout == LayoutPreview && m.CurrentNote != nil {
+		// Mouse wheel scrolling.
+		m.PreviewView, cmd = m.PreviewView.Update(msg)
+		return m, cmd
+	}
+	if mouse, ok := msg.(tea.MouseMsg); ok && m.splitShown() && m.CurrentNote != nil && tea.MouseEvent(mouse).IsWheel() {
+		m.scrollSplit(mouse)
+		return m, nil
+	}
 	if m.CurrentNote != nil {
+		before := m.NoteContent.Value()
 		m.NoteContent, cmd = m.NoteContent.Update(msg)
+		if m.NoteContent.Value() != before && m.ReadOnly {
+			line := m.NoteContent.Line()
+			m.NoteContent.SetValue(before)
+			m.gotoLine(line)
+			m.ErrMsg = "This note is read-only while another Totion instance has it open"
+		} else if m.NoteContent.Value() != before {
+			m.markDirty()
+		}
 		currentLen := len(m.NoteContent.Value())
 		if currentLen != m.PrevNoteLength {
 			m.SuggesTimeCount = 0
 			m.Suggestion = ""
 		}
 		m.PrevNoteLength = currentLen
+		if m.Layout == LayoutSplit {
+			if m.NoteContent.Value() != before {
+				cmd = tea.Batch(cmd, m.editorChanged())
+			}
+			m.syncPreview()
+		}
 	}
 	return m, cmd
 }
 
+// updateSearch routes input on the search screen: navigation keys move
+// through the results, everything else edits the query and restarts the
+// search in the background.
+func (m Model) updateSearch(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	if key, ok := msg.(tea.KeyMsg); ok {
+		switch key.String() {
+		case "up", "down", "pgup", "pgdown":
+			m.SearchResults, cmd = m.SearchResults.Update(msg)
+			return m, cmd
+		}
+	}
+	before := m.SearchInput.Value()
+	m.SearchInput, cmd = m.SearchInput.Update(msg)
+	if m.SearchInput.Value() == before {
+		return m, cmd
+	}
+	if strings.TrimSpace(m.SearchInput.Value()) == "" {
+		m.SearchSeq++
+		m.SearchResults.Title = "Search results"
+		return m, tea.Batch(cmd, m.SearchResults.SetItems(nil))
+	}
+	return m, tea.Batch(cmd, m.searchCmd())
+}
+
+// gotoLine moves the editor cursor to the start of the given 0-based line.
+func (m *Model) gotoLine(line int) {
+	m.NoteContent.CursorStart()
+	for m.NoteContent.Line() > line {
+		m.NoteContent.CursorUp()
+	}
+	m.NoteContent.CursorStart()
+	// Let the textarea scroll its viewport to the new cursor position.
+	m.NoteContent, _ = m.NoteContent.Update(nil)
+}
+
+// SetStore switches the model to another note store, along with the
+// search index and history kept inside it.
+func (m *Model) SetStore(s store.NoteStore) {
+	m.Store = s
+	m.Index = search.OpenIndex(s)
+	m.History = history.New(s)
+	m.Trash = trash.New(s)
+	m.Links = links.NewIndex()
+	m.Trash.RetainFor = m.Config.TrashRetention()
+	m.Locker = lock.New(s)
+}
+
+// refreshList reloads the list with the contents of CurrentDir, falling
+// back to the root when the folder no longer exists. With a TagFilter set
+// it lists the notes carrying that tag from every folder instead.
+func (m *Model) refreshList() {
+	if m.JournalFilter {
+		items, err := m.journalItems()
+		if err != nil {
+			m.ErrMsg = fmt.Sprintf("Error listing notes: %v", err)
+			return
+		}
+		m.List.Title = "Journal 📅"
+		m.List.SetItems(items)
+		return
+	}
+	if m.TagFilter != "" {
+		notes, err := file.AllNotes(m.Store)
+		if err != nil {
+			m.ErrMsg = fmt.Sprintf("Error listing notes: %v", err)
+			return
+		}
+		m.List.Title = file.Breadcrumbs("") + " › #" + m.TagFilter
+		m.List.SetItems(file.BuildTagIndex(notes).NoteItems(m.TagFilter))
+		return
+	}
+	items, err := file.NotesFiles(m.Store, m.CurrentDir)
+	if errors.Is(err, fs.ErrNotExist) && m.CurrentDir != "" {
+		m.CurrentDir = ""
+		items, err = file.NotesFiles(m.Store, m.CurrentDir)
+	}
+	if err != nil {
+		m.ErrMsg = fmt.Sprintf("Error listing notes: %v", err)
+		return
+	}
+	m.List.Title = file.Breadcrumbs(m.CurrentDir)
+	m.List.SetItems(items)
+	m.ListStamp = m.listStamp()
+}
+
+func (m *Model) markDirty() {
+	m.Dirty = true
+	m.LastEdit = time.Now()
+}
+
+// autoSaveDue reports whether unsaved edits should be written at now, either
+// because typing has paused for AutoSaveIdle or AutoSaveInterval has passed
+// since the last save.
+func (m Model) autoSaveDue(now time.Time) bool {
+	if !m.Dirty {
+		return false
+	}
+	if m.AutoSaveIdle > 0 && now.Sub(m.LastEdit) >= m.AutoSaveIdle {
+		return true
+	}
+	if m.AutoSaveInterval > 0 {
+		since := m.LastSaved
+		if since.IsZero() {
+			since = m.LastEdit
+		}
+		return now.Sub(since) >= m.AutoSaveInterval
+	}
+	return false
+}
+
 func (m Model) View() string {
 	// available width for text wrapping
 	h, _ := styles.DocStyle.GetFrameSize()
@@ -335,33 +932,90 @@ func (m Model) View() string {
 	}
 	errView := ""
 	if m.ErrMsg != "" {
-		errStyle := styles.DocStyle.Foreground(styles.CursorStyle.GetForeground()).Bold(true).Margin(0, 0, 1, 0).Width(availableWidth)
+		errStyle := styles.ErrorStyle.Margin(0, 0, 1, 0).Width(availableWidth)
 		errView = errStyle.Render(m.ErrMsg) + "\n"
 	}
 	var view string
-	var help string = GeneralHelp // Default GeneralHelp
+	var help string = m.Keys.GeneralHelp()
 	if m.CreateFileInputVisible {
-		view = m.NewFileInput.View()
-		help = GeneralHelp
+		kind := "note"
+		if m.InputMode == InputFolder {
+			kind = "folder"
+		}
+		location := "All Notes"
+		if m.CurrentDir != "" {
+			location = m.CurrentDir + "/"
+		}
+		view = fmt.Sprintf("New %s in %s\n\n%s", kind, location, m.NewFileInput.View())
+		if m.InputMode == InputRename {
+			view = fmt.Sprintf("Rename %s\n\n%s", m.RenamePath, m.NewFileInput.View())
+		}
+		help = m.Keys.GeneralHelp()
+	} else if m.LockOwner != nil && m.CurrentNote != nil {
+		view = m.lockView()
+		help = m.Keys.LockHelp()
+	} else if m.Conflict && m.CurrentNote != nil {
+		view = m.conflictView()
+		help = m.Keys.ConflictHelp()
+	} else if m.HistoryVisible {
+		view = m.historyView()
+		help = m.Keys.HistoryHelp()
+	} else if m.BacklinksVisible && m.CurrentNote != nil {
+		view = m.backlinksView()
+		help = m.Keys.BacklinksHelp()
+	} else if m.GraphVisible && m.CurrentNote != nil {
+		view = m.graphView()
+		help = m.Keys.GraphHelp()
+	} else if m.TrashVisible {
+		view = m.trashView()
+		help = m.Keys.TrashHelp()
+	} else if m.Layout == LayoutPreview && m.CurrentNote != nil {
+		view = m.PreviewView.View()
+		help = m.Keys.PreviewHelp()
 	} else if m.CurrentNote != nil {
 		view = m.NoteContent.View()
+		if m.splitShown() {
+			view = m.splitView()
+		}
 		if m.AutoCompleteEnabled && m.Suggestion != "" {
-			suggStyle := lipgloss.NewStyle().Italic(true).Foreground(lipgloss.Color("#f1e588ff"))
-			suggestionText := suggStyle.Render(m.Suggestion)
+			suggestionText := styles.SuggestionStyle.Render(m.Suggestion)
 			suggestionLineStyle := lipgloss.NewStyle().Width(availableWidth)
-			suggestionLine := suggestionLineStyle.Render(fmt.Sprintf("Suggestion: %s (Tab to accept)", suggestionText))
+			suggestionLine := suggestionLineStyle.Render(fmt.Sprintf("Suggestion: %s (%s to accept)", suggestionText, m.Keys.AcceptSuggestion.Help().Key))
 			view += "\n" + suggestionLine + "\n"
 		}
-		help = SaveHelp
+		if link, ok := m.linkUnderCursor(); ok && m.Keys.FollowLink.Enabled() {
+			view += "\n" + styles.HighlightStyle.Render(fmt.Sprintf("→ %s (%s to follow)", link.Target, m.Keys.FollowLink.Help().Key))
+		}
+		help = m.Keys.EditorHelp()
+		if m.isJournal() {
+			help += "\n" + m.Keys.JournalHelp()
+		}
 	} else if m.ListVisible {
 		if len(m.List.Items()) == 0 {
-			view = "All Notes 📒\n\nNo notes yet. Press Ctrl+N to create one."
+			view = m.List.Title + "\n\nNo notes yet. Press " + m.Keys.NewNote.Help().Key + " to create one."
 		} else {
 			view = m.List.View()
 		}
-		help = ListHelp
+		help = m.Keys.ListHelp()
+	} else if m.SearchVisible {
+		view = "Search notes 🔍\n\n" + m.SearchInput.View() + "\n\n"
+		if len(m.SearchResults.Items()) == 0 {
+			if strings.TrimSpace(m.SearchInput.Value()) != "" {
+				view += "No matches."
+			}
+		} else {
+			view += m.SearchResults.View()
+		}
+		help = m.Keys.SearchHelp()
+	} else if m.TagsVisible {
+		if len(m.Tags.Items()) == 0 {
+			view = m.Tags.Title + "\n\nNo tags yet. Add tags in front matter or write #tag in a note."
+		} else {
+			view = m.Tags.View()
+		}
+		help = m.Keys.TagHelp()
 	} else {
-		view = "No note open. Press Ctrl+N to create one or Ctrl+L to list existing notes."
+		view = fmt.Sprintf("No note open. Press %s to create one or %s to list existing notes.", m.Keys.NewNote.Help().Key, m.Keys.List.Help().Key)
 	}
 	welcome := styles.WelcomeStyle.Render("Welcome to the TOTION 🧠")
 	asciiArt := AsciiArt // defined in the data.go
@@ -375,11 +1029,21 @@ func (m Model) View() string {
 		}
 		var nextSuggestion string
 		if m.AutoCompleteEnabled {
-			nextSuggestion = "Ctrl+G: Get the next suggestion"
+			nextSuggestion = m.Keys.NextSuggestion.Help().Key + ": Get the next suggestion"
 		} else {
 			nextSuggestion = ""
 		}
-		statusText := fmt.Sprintf("[Autocomplete: %s - Ctrl+T to toggle]", status)
+		statusText := fmt.Sprintf("[Autocomplete: %s - %s to toggle]", status, m.Keys.ToggleAutocomplete.Help().Key)
+		if m.Client == nil {
+			statusText = "[AI disabled: " + m.AIDisabled + "]"
+		}
+		if m.ReadOnly {
+			statusText = "🔒 read-only  " + statusText
+		} else if m.Dirty {
+			statusText = "● unsaved changes  " + statusText
+		} else if !m.LastSaved.IsZero() {
+			statusText = fmt.Sprintf("saved at %s  ", m.LastSaved.Format("15:04:05")) + statusText
+		}
 		if nextSuggestion != "" {
 			statusText += "\n" + nextSuggestion
 		}
@@ -389,42 +1053,111 @@ func (m Model) View() string {
 	return fmt.Sprintf("%s\n%s%s%s\n%s\n\n%s\n\n%s", welcome, errView, totionView, autocompleteStatus, description, view, help)
 }
 
-func InitialModel() Model {
-	ti := tui.NewTextInput()
+// InitialModel builds the model for the notes in cfg.NotesDir, which the
+// caller resolves and creates beforehand.
+func InitialModel(cfg config.Config) Model {
+	ti := tui.NewTextInput(cfg.Editor)
 	nt := tui.NewTextArea()
-	noteList := file.NotesFiles(NotesDir)
-	finallist := list.New(noteList, list.NewDefaultDelegate(), 0, 0)
-	finallist.Title = "All Notes 📒"
+	noteStore := store.NewFSStore(cfg.NotesDir)
+	errMsg := ""
+	noteList, err := file.NotesFiles(noteStore, "")
+	if err != nil {
+		errMsg = fmt.Sprintf("Error listing notes: %v", err)
+	}
+	finallist := list.New(noteList, styles.ListDelegate(), 0, 0)
+	finallist.Title = file.Breadcrumbs("")
 	finallist.Styles.Title = styles.ListTitleStyle
-	var client *genai.Client
-	if Api_key != "" {
-		ctx := context.Background()
-		var err error
-		client, err = genai.NewClient(ctx, &genai.ClientConfig{
-			APIKey:  Api_key,
-			Backend: genai.BackendGeminiAPI,
-		})
-		if err != nil {
-			log.Printf("Failed to initialise the gemini client: %v", err)
-			client = nil
-		}
-	} else {
-		log.Printf("Api key is not set, AI Suggestion is disabled.")
+	searchInput := textinput.New()
+	searchInput.Placeholder = "Search note contents..."
+	searchInput.Prompt = "🔍 "
+	searchInput.Cursor.Style = styles.CursorStyle
+	searchResults := list.New(nil, styles.ListDelegate(), 0, 0)
+	searchResults.Title = "Search results"
+	searchResults.Styles.Title = styles.ListTitleStyle
+	searchResults.SetFilteringEnabled(false)
+	searchResults.SetShowHelp(false)
+	backlinksList := list.New(nil, styles.ListDelegate(), 0, 0)
+	backlinksList.Styles.Title = styles.ListTitleStyle
+	backlinksList.SetFilteringEnabled(false)
+	backlinksList.SetShowHelp(false)
+	historyList := list.New(nil, styles.ListDelegate(), 0, 0)
+	historyList.Title = "History 🕘"
+	historyList.Styles.Title = styles.ListTitleStyle
+	historyList.SetFilteringEnabled(false)
+	historyList.SetShowHelp(false)
+	trashList := list.New(nil, styles.ListDelegate(), 0, 0)
+	trashList.Title = "Trash 🗑️"
+	trashList.Styles.Title = styles.ListTitleStyle
+	trashList.SetFilteringEnabled(false)
+	trashList.SetShowHelp(false)
+	noteTrash := trash.New(noteStore)
+	noteTrash.RetainFor = cfg.TrashRetention()
+	if _, err := noteTrash.PurgeExpired(); err != nil {
+		errMsg = fmt.Sprintf("Error purging trash: %v", err)
+	}
+	tagList := list.New(nil, styles.ListDelegate(), 0, 0)
+	tagList.Title = "Tags 🏷️"
+	tagList.Styles.Title = styles.ListTitleStyle
+	client, aiDisabled := newClient(cfg.Autocomplete.APIKey)
+	// Sized like the WindowSizeMsg handler would for the default 80x24.
+	frameWidth, frameHeight := styles.DocStyle.GetFrameSize()
+	keys, err := keymap.New(cfg.Keys)
+	if err != nil {
+		// The config file is validated when it is loaded; this only guards
+		// configs built in code.
+		keys = keymap.Default()
+		errMsg = fmt.Sprintf("Key bindings: %v", err)
 	}
 	return Model{
+		Config:                 cfg,
+		Keys:                   keys,
 		NewFileInput:          	ti,
 		CreateFileInputVisible: false,
 		NoteContent:           	nt,
+		Store:                  noteStore,
+		Index:                  search.OpenIndex(noteStore),
+		History:                history.New(noteStore),
+		HistoryList:            historyList,
+		Links:                  links.NewIndex(),
+		BacklinksList:          backlinksList,
+		GraphView:              viewport.New(80-frameWidth, 24-frameHeight-12),
+		HistoryDiff:            viewport.New(0, 0),
+		PreviewView:            viewport.New(80-frameWidth, 24-frameHeight-10),
+		Trash:                  noteTrash,
+		Locker:                 lock.New(noteStore),
+		TrashList:              trashList,
 		List:                   finallist,
+		Tags:                   tagList,
+		SearchInput:            searchInput,
+		SearchResults:          searchResults,
 		ListVisible:            false,
-		ErrMsg:                 "",
+		ErrMsg:                 errMsg,
 		Ctx:                    context.Background(),
 		Client:                 client,
+		AIDisabled:             aiDisabled,
 		Suggestion:             "",
-		AutoCompleteEnabled:    false,
+		AutoCompleteEnabled:    cfg.Autocomplete.Enabled && client != nil,
 		Width:                  80,
 		Height:                 24,
 		SuggesTimeCount:        0,
 		PrevNoteLength:         0,
+		AutoSaveIdle:           cfg.Editor.AutoSaveIdle,
+		AutoSaveInterval:       cfg.Editor.AutoSaveInterval,
 	}
-}
\ No newline at end of file
+}
+
+// newClient creates the Gemini client for autocomplete. Without one it
+// returns the reason AI is disabled, to be shown in the UI.
+func newClient(apiKey string) (*genai.Client, string) {
+	if apiKey == "" {
+		return nil, "no Gemini API key; set " + config.EnvAPIKey + " or run `totion auth set`"
+	}
+	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
+		APIKey:  apiKey,
+		Backend: genai.BackendGeminiAPI,
+	})
+	if err != nil {
+		return nil, fmt.Sprintf("could not start the Gemini client: %v", err)
+	}
+	return client, ""
+}
@@ -2,37 +2,49 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
-
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/config"
+	"github.com/AbhaySingh002/Totion/internal/file"
+	"github.com/AbhaySingh002/Totion/internal/search"
+	"github.com/AbhaySingh002/Totion/internal/store"
+	"github.com/AbhaySingh002/Totion/internal/styles"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
-// setupTestNotesDir creates a temporary directory for testing and sets NotesDir
+// setupTestNotesDir creates a temporary notes directory for testing
 func setupTestNotesDir(t *testing.T) string {
 	tmpDir, err := os.MkdirTemp("", "totion-app-test-*")
 	if err != nil {
 		t.Fatalf("Failed to create temp dir: %v", err)
 	}
 
-	// Save original NotesDir
-	originalNotesDir := NotesDir
-	NotesDir = tmpDir
-
-	// Cleanup function to restore original
 	t.Cleanup(func() {
 		os.RemoveAll(tmpDir)
-		NotesDir = originalNotesDir
 	})
 
 	return tmpDir
 }
 
+// testConfig returns the default settings for a model working on notesDir
+func testConfig(notesDir string) config.Config {
+	cfg := config.Default()
+	cfg.NotesDir = notesDir
+	return cfg
+}
+
 // createTestNoteFile creates a test note file
-func createTestNoteFile(t *testing.T, name, content string) string {
-	filePath := filepath.Join(NotesDir, name+".md")
+func createTestNoteFile(t *testing.T, dir, name, content string) string {
+	filePath := filepath.Join(dir, name+".md")
 	err := os.WriteFile(filePath, []byte(content), 0644)
 	if err != nil {
 		t.Fatalf("Failed to create test note: %v", err)
@@ -44,7 +56,7 @@ func TestInitialModel(t *testing.T) {
 	tmpDir := setupTestNotesDir(t)
 	defer os.RemoveAll(tmpDir)
 
-	model := InitialModel()
+	model := InitialModel(testConfig(tmpDir))
 
 	if model.CreateFileInputVisible != false {
 		t.Errorf("Expected CreateFileInputVisible to be false, got %v", model.CreateFileInputVisible)
@@ -84,10 +96,10 @@ func TestModel_OpenOrCreateFile(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	t.Run("create new file", func(t *testing.T) {
-		model := InitialModel()
-		filePath := filepath.Join(NotesDir, "newfile.md")
+		model := InitialModel(testConfig(tmpDir))
+		filePath := filepath.Join(tmpDir, "newfile.md")
 
-		err := model.OpenOrCreateFile(filePath)
+		err := model.OpenOrCreateFile("newfile.md")
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -104,17 +116,13 @@ func TestModel_OpenOrCreateFile(t *testing.T) {
 		if _, err := os.Stat(filePath); os.IsNotExist(err) {
 			t.Error("Expected file to be created, but it doesn't exist")
 		}
-
-		if model.CurrentNote != nil {
-			model.CurrentNote.Close()
-		}
 	})
 
 	t.Run("open existing file", func(t *testing.T) {
-		model := InitialModel()
-		filePath := createTestNoteFile(t, "existing", "existing content")
+		model := InitialModel(testConfig(tmpDir))
+		createTestNoteFile(t, tmpDir, "existing", "existing content")
 
-		err := model.OpenOrCreateFile(filePath)
+		err := model.OpenOrCreateFile("existing.md")
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -127,17 +135,13 @@ func TestModel_OpenOrCreateFile(t *testing.T) {
 		if content != "existing content" {
 			t.Errorf("Expected content 'existing content', got '%s'", content)
 		}
-
-		if model.CurrentNote != nil {
-			model.CurrentNote.Close()
-		}
 	})
 
 	t.Run("handle file with content", func(t *testing.T) {
-		model := InitialModel()
-		filePath := createTestNoteFile(t, "testfile", "line 1\nline 2\nline 3")
+		model := InitialModel(testConfig(tmpDir))
+		createTestNoteFile(t, tmpDir, "testfile", "line 1\nline 2\nline 3")
 
-		err := model.OpenOrCreateFile(filePath)
+		err := model.OpenOrCreateFile("testfile.md")
 		if err != nil {
 			t.Fatalf("Expected no error, got %v", err)
 		}
@@ -146,10 +150,6 @@ func TestModel_OpenOrCreateFile(t *testing.T) {
 		if !strings.Contains(content, "line 1") {
 			t.Errorf("Expected content to contain 'line 1', got '%s'", content)
 		}
-
-		if model.CurrentNote != nil {
-			model.CurrentNote.Close()
-		}
 	})
 }
 
@@ -158,10 +158,10 @@ func TestModel_SaveNote(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	t.Run("save note with content", func(t *testing.T) {
-		model := InitialModel()
-		filePath := createTestNoteFile(t, "savetest", "original content")
+		model := InitialModel(testConfig(tmpDir))
+		filePath := createTestNoteFile(t, tmpDir, "savetest", "original content")
 
-		err := model.OpenOrCreateFile(filePath)
+		err := model.OpenOrCreateFile("savetest.md")
 		if err != nil {
 			t.Fatalf("Failed to open file: %v", err)
 		}
@@ -192,7 +192,7 @@ func TestModel_SaveNote(t *testing.T) {
 	})
 
 	t.Run("save note when CurrentNote is nil", func(t *testing.T) {
-		model := InitialModel()
+		model := InitialModel(testConfig(tmpDir))
 
 		// Should not panic or error when CurrentNote is nil
 		model.SaveNote()
@@ -203,10 +203,10 @@ func TestModel_SaveNote(t *testing.T) {
 	})
 
 	t.Run("save empty note", func(t *testing.T) {
-		model := InitialModel()
-		filePath := createTestNoteFile(t, "empty", "original")
+		model := InitialModel(testConfig(tmpDir))
+		filePath := createTestNoteFile(t, tmpDir, "empty", "original")
 
-		err := model.OpenOrCreateFile(filePath)
+		err := model.OpenOrCreateFile("empty.md")
 		if err != nil {
 			t.Fatalf("Failed to open file: %v", err)
 		}
@@ -230,7 +230,7 @@ func TestModel_Update(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	t.Run("window size message", func(t *testing.T) {
-		model := InitialModel()
+		model := InitialModel(testConfig(tmpDir))
 		originalWidth := model.Width
 		originalHeight := model.Height
 
@@ -264,7 +264,7 @@ func TestModel_Update(t *testing.T) {
 	})
 
 	t.Run("suggestion message with error", func(t *testing.T) {
-		model := InitialModel()
+		model := InitialModel(testConfig(tmpDir))
 
 		newModel, cmd := model.Update(suggestionMsg{
 			suggestion: "",
@@ -290,7 +290,7 @@ func TestModel_Update(t *testing.T) {
 	})
 
 	t.Run("suggestion message success", func(t *testing.T) {
-		model := InitialModel()
+		model := InitialModel(testConfig(tmpDir))
 
 		newModel, cmd := model.Update(suggestionMsg{
 			suggestion: "This is a suggestion",
@@ -312,7 +312,7 @@ func TestModel_Update(t *testing.T) {
 	})
 
 	t.Run("init command", func(t *testing.T) {
-		model := InitialModel()
+		model := InitialModel(testConfig(tmpDir))
 		cmd := model.Init()
 
 		if cmd == nil {
@@ -326,7 +326,7 @@ func TestModel_generateSuggestionCmd(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 	
 	t.Run("nil client", func(t *testing.T) {
-		model := InitialModel()
+		model := InitialModel(testConfig(tmpDir))
 		model.Client = nil
 
 		cmd := model.generateSuggestionCmd()
@@ -352,7 +352,7 @@ func TestModel_View(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	t.Run("default view", func(t *testing.T) {
-		model := InitialModel()
+		model := InitialModel(testConfig(tmpDir))
 		view := model.View()
 
 		if !strings.Contains(view, "Welcome to the TOTION") {
@@ -365,7 +365,7 @@ func TestModel_View(t *testing.T) {
 	})
 
 	t.Run("view with error message", func(t *testing.T) {
-		model := InitialModel()
+		model := InitialModel(testConfig(tmpDir))
 		model.ErrMsg = "Test error message"
 
 		view := model.View()
@@ -375,10 +375,10 @@ func TestModel_View(t *testing.T) {
 	})
 
 	t.Run("view when note is open", func(t *testing.T) {
-		model := InitialModel()
-		filePath := createTestNoteFile(t, "viewtest", "test content")
+		model := InitialModel(testConfig(tmpDir))
+		createTestNoteFile(t, tmpDir, "viewtest", "test content")
 
-		err := model.OpenOrCreateFile(filePath)
+		err := model.OpenOrCreateFile("viewtest.md")
 		if err != nil {
 			t.Fatalf("Failed to open file: %v", err)
 		}
@@ -387,17 +387,13 @@ func TestModel_View(t *testing.T) {
 		if !strings.Contains(view, "Ctrl+S: Save Note") {
 			t.Error("Expected view to contain save help when note is open")
 		}
-
-		if model.CurrentNote != nil {
-			model.CurrentNote.Close()
-		}
 	})
 
 	t.Run("view with suggestion", func(t *testing.T) {
-		model := InitialModel()
-		filePath := createTestNoteFile(t, "sugtest", "content")
+		model := InitialModel(testConfig(tmpDir))
+		createTestNoteFile(t, tmpDir, "sugtest", "content")
 
-		err := model.OpenOrCreateFile(filePath)
+		err := model.OpenOrCreateFile("sugtest.md")
 		if err != nil {
 			t.Fatalf("Failed to open file: %v", err)
 		}
@@ -413,9 +409,1508 @@ func TestModel_View(t *testing.T) {
 		if !strings.Contains(view, "Tab to accept") {
 			t.Error("Expected view to contain tab instruction")
 		}
+	})
+}
 
-		if model.CurrentNote != nil {
-			model.CurrentNote.Close()
+func TestModel_MemStore(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	model := InitialModel(testConfig(tmpDir))
+	model.SetStore(store.NewMemStore())
+
+	if err := model.OpenOrCreateFile("memo.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	model.NoteContent.SetValue("in memory")
+	model.SaveNote()
+
+	data, err := model.Store.Read("memo.md")
+	if err != nil {
+		t.Fatalf("Failed to read from store: %v", err)
+	}
+	if string(data) != "in memory" {
+		t.Errorf("Expected content 'in memory', got '%s'", string(data))
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "memo.md")); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written to the notes directory")
+	}
+
+	model.ListVisible = true
+	model.refreshList()
+	newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyDelete})
+	if ok, _ := store.Exists(newModel.(Model).Store, "memo.md"); ok {
+		t.Error("Expected note to be removed from the store")
+	}
+}
+
+// failingStore is a NoteStore whose writes always fail.
+type failingStore struct {
+	*store.MemStore
+}
+
+func (failingStore) Write(name string, data []byte) error {
+	return errors.New("disk full")
+}
+
+func TestModel_SaveNoteFailure(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	mem := store.NewMemStore()
+	mem.Write("keep.md", []byte("original"))
+
+	model := InitialModel(testConfig(tmpDir))
+	model.SetStore(failingStore{mem})
+	if err := model.OpenOrCreateFile("keep.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	model.NoteContent.SetValue("unsaved edits")
+
+	t.Run("save error keeps note open", func(t *testing.T) {
+		m := model
+		if err := m.SaveNote(); err == nil {
+			t.Fatal("Expected SaveNote to return an error")
+		}
+		if !strings.Contains(m.ErrMsg, "disk full") {
+			t.Errorf("Expected error message to mention the failure, got '%s'", m.ErrMsg)
+		}
+		if m.CurrentNote == nil || m.NoteContent.Value() != "unsaved edits" {
+			t.Error("Expected note and content to stay open after a failed save")
+		}
+		data, _ := mem.Read("keep.md")
+		if string(data) != "original" {
+			t.Errorf("Expected original content to be intact, got '%s'", string(data))
+		}
+	})
+
+	t.Run("esc does not close the note", func(t *testing.T) {
+		newModel, _ := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if newModel.(Model).CurrentNote == nil {
+			t.Error("Expected note to remain open when saving on Esc fails")
+		}
+	})
+
+	t.Run("ctrl+c needs confirmation", func(t *testing.T) {
+		newModel, cmd := model.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+		if cmd != nil {
+			t.Fatal("Expected first Ctrl+C not to quit after a failed save")
+		}
+		_, cmd = newModel.Update(tea.KeyMsg{Type: tea.KeyCtrlC})
+		if cmd == nil {
+			t.Error("Expected second Ctrl+C to quit")
+		}
+	})
+}
+
+func TestModel_AutoSave(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	newModel := func(t *testing.T) Model {
+		model := InitialModel(testConfig(tmpDir))
+		model.SetStore(store.NewMemStore())
+		if err := model.OpenOrCreateFile("auto.md"); err != nil {
+			t.Fatalf("Failed to open file: %v", err)
+		}
+		return model
+	}
+
+	t.Run("typing marks the note dirty", func(t *testing.T) {
+		model := newModel(t)
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+		if !updated.(Model).Dirty {
+			t.Error("Expected note to be dirty after typing")
+		}
+	})
+
+	t.Run("ctrl+s saves and keeps the note open", func(t *testing.T) {
+		model := newModel(t)
+		model.NoteContent.SetValue("draft")
+		model.Dirty = true
+
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+		m := updated.(Model)
+		if m.CurrentNote == nil || m.NoteContent.Value() != "draft" {
+			t.Error("Expected note to stay open after Ctrl+S")
+		}
+		if m.Dirty || m.LastSaved.IsZero() {
+			t.Error("Expected note to be marked as saved")
+		}
+		if !strings.Contains(m.View(), "saved at") {
+			t.Error("Expected view to show the saved-at indicator")
+		}
+	})
+
+	t.Run("tick saves after idle period", func(t *testing.T) {
+		model := newModel(t)
+		model.NoteContent.SetValue("idle text")
+		model.Dirty = true
+		model.LastEdit = time.Now().Add(-time.Minute)
+
+		updated, cmd := model.Update(tickMsg{gen: model.TickGen, time: time.Now()})
+		if cmd == nil {
+			t.Error("Expected tick loop to continue")
+		}
+		if updated.(Model).Dirty {
+			t.Error("Expected autosave to clear the dirty flag")
+		}
+		data, _ := model.Store.Read("auto.md")
+		if string(data) != "idle text" {
+			t.Errorf("Expected autosaved content 'idle text', got '%s'", string(data))
+		}
+	})
+
+	t.Run("tick waits while typing", func(t *testing.T) {
+		model := newModel(t)
+		model.Dirty = true
+		model.LastEdit = time.Now()
+		model.LastSaved = time.Now()
+
+		updated, _ := model.Update(tickMsg{gen: model.TickGen, time: time.Now()})
+		if !updated.(Model).Dirty {
+			t.Error("Expected no autosave while the user is still typing")
+		}
+	})
+
+	t.Run("stale tick is ignored", func(t *testing.T) {
+		model := newModel(t)
+		_, cmd := model.Update(tickMsg{gen: model.TickGen - 1, time: time.Now()})
+		if cmd != nil {
+			t.Error("Expected stale tick to stop")
+		}
+	})
+}
+
+func TestModel_Folders(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	model := InitialModel(testConfig(tmpDir))
+	mem := store.NewMemStore()
+	mem.MakeDir("work")
+	mem.Write("work/plan.md", []byte("plan"))
+	model.SetStore(mem)
+
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.Update(msg)
+		return updated.(Model)
+	}
+
+	model = press(model, tea.KeyMsg{Type: tea.KeyCtrlL})
+	if len(model.List.Items()) != 1 {
+		t.Fatalf("Expected the root to list one folder, got %d items", len(model.List.Items()))
+	}
+
+	t.Run("enter opens folder", func(t *testing.T) {
+		m := press(model, tea.KeyMsg{Type: tea.KeyEnter})
+		if m.CurrentDir != "work" {
+			t.Fatalf("Expected CurrentDir 'work', got '%s'", m.CurrentDir)
+		}
+		if !strings.Contains(m.List.Title, "work") {
+			t.Errorf("Expected breadcrumbs in list title, got '%s'", m.List.Title)
+		}
+
+		m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+		if m.CurrentDir != "" || !m.ListVisible {
+			t.Errorf("Expected Esc to return to the parent folder, got dir '%s'", m.CurrentDir)
+		}
+	})
+
+	t.Run("new note is created in the current folder", func(t *testing.T) {
+		m := press(model, tea.KeyMsg{Type: tea.KeyEnter})
+		m = press(m, tea.KeyMsg{Type: tea.KeyCtrlN})
+		m.NewFileInput.SetValue("todo")
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if m.CurrentNote == nil || m.CurrentNote.Path() != "work/todo.md" {
+			t.Fatalf("Expected work/todo.md to be open, got %v", m.CurrentNote)
+		}
+	})
+
+	t.Run("create and delete folder", func(t *testing.T) {
+		m := press(model, tea.KeyMsg{Type: tea.KeyCtrlF})
+		if !m.CreateFileInputVisible || m.InputMode != InputFolder {
+			t.Fatal("Expected folder name prompt")
+		}
+		m.NewFileInput.SetValue("archive")
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if ok, _ := store.Exists(mem, "archive"); !ok {
+			t.Fatal("Expected folder 'archive' to be created")
+		}
+		if !m.ListVisible {
+			t.Error("Expected list to be shown after creating a folder")
+		}
+
+		m.List.Select(0)
+		m = press(m, tea.KeyMsg{Type: tea.KeyDelete})
+		if ok, _ := store.Exists(mem, "archive"); ok {
+			t.Error("Expected empty folder 'archive' to be deleted")
+		}
+
+		m = press(m, tea.KeyMsg{Type: tea.KeyDelete})
+		if ok, _ := store.Exists(mem, "work"); !ok {
+			t.Error("Expected non-empty folder 'work' to be kept")
+		}
+		if m.ErrMsg == "" {
+			t.Error("Expected an error when deleting a non-empty folder")
+		}
+	})
+}
+
+func TestModel_FrontMatter(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	t.Run("new note gets created stamp", func(t *testing.T) {
+		model := InitialModel(testConfig(tmpDir))
+		if err := model.OpenOrCreateFile("fresh.md"); err != nil {
+			t.Fatalf("Failed to create file: %v", err)
+		}
+		if !strings.HasPrefix(model.NoteContent.Value(), "---\ncreated: ") {
+			t.Errorf("Expected created front matter, got '%s'", model.NoteContent.Value())
+		}
+		if model.CurrentNote.Meta().Created.IsZero() {
+			t.Error("Expected CurrentNote to carry the created time")
+		}
+	})
+
+	t.Run("saving keeps the block intact", func(t *testing.T) {
+		content := "---\ntitle: Kept\ntags: [a]\nowner: me\n---\nbody"
+		filePath := createTestNoteFile(t, tmpDir, "kept", content)
+
+		model := InitialModel(testConfig(tmpDir))
+		if err := model.OpenOrCreateFile("kept.md"); err != nil {
+			t.Fatalf("Failed to open file: %v", err)
+		}
+		model.NoteContent.SetValue(model.NoteContent.Value() + " more")
+		model.SaveNote()
+
+		saved, _ := os.ReadFile(filePath)
+		if string(saved) != content+" more" {
+			t.Errorf("Expected front matter to be preserved, got '%s'", string(saved))
+		}
+	})
+}
+
+func TestModel_TagBrowser(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	model := InitialModel(testConfig(tmpDir))
+	mem := store.NewMemStore()
+	mem.Write("a.md", []byte("#work stuff"))
+	mem.MakeDir("folder")
+	mem.Write("folder/b.md", []byte("---\ntags: [work, home]\n---\n"))
+	mem.Write("c.md", []byte("untagged"))
+	model.SetStore(mem)
+	sized, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 60})
+	model = sized.(Model)
+
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.Update(msg)
+		return updated.(Model)
+	}
+
+	model = press(model, tea.KeyMsg{Type: tea.KeyCtrlL})
+	model = press(model, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("#")})
+	if !model.TagsVisible {
+		t.Fatal("Expected tag browser to be visible")
+	}
+	if len(model.Tags.Items()) != 2 {
+		t.Fatalf("Expected 2 tags, got %d", len(model.Tags.Items()))
+	}
+	if !strings.Contains(model.View(), "#work") {
+		t.Error("Expected view to list the work tag")
+	}
+
+	model = press(model, tea.KeyMsg{Type: tea.KeyEnter})
+	if model.TagFilter != "work" || !model.ListVisible {
+		t.Fatalf("Expected list filtered to 'work', got filter '%s'", model.TagFilter)
+	}
+	if len(model.List.Items()) != 2 {
+		t.Errorf("Expected 2 notes tagged work, got %d", len(model.List.Items()))
+	}
+	if !strings.Contains(model.List.Title, "#work") {
+		t.Errorf("Expected list title to show the tag, got '%s'", model.List.Title)
+	}
+
+	model = press(model, tea.KeyMsg{Type: tea.KeyEsc})
+	if model.TagFilter != "" || len(model.List.Items()) != 3 {
+		t.Errorf("Expected Esc to clear the tag filter, got filter '%s' with %d items", model.TagFilter, len(model.List.Items()))
+	}
+}
+
+func TestModel_Search(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	model := InitialModel(testConfig(tmpDir))
+	mem := store.NewMemStore()
+	mem.Write("ideas.md", []byte("line one\nline two\nthe secret plan\nline four"))
+	model.SetStore(mem)
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k"), Alt: true})
+	model = updated.(Model)
+	if !model.SearchVisible {
+		t.Fatal("Expected search screen to be visible")
+	}
+
+	var cmd tea.Cmd
+	for _, r := range "secret" {
+		updated, cmd = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		model = updated.(Model)
+	}
+	if model.SearchInput.Value() != "secret" {
+		t.Fatalf("Expected query 'secret', got '%s'", model.SearchInput.Value())
+	}
+
+	// Run the search the last keystroke started and feed the result back.
+	var result searchResultMsg
+	for _, msg := range cmd().(tea.BatchMsg) {
+		if msg == nil {
+			continue
+		}
+		if r, ok := msg().(searchResultMsg); ok {
+			result = r
+		}
+	}
+	if len(result.matches) != 1 {
+		t.Fatalf("Expected 1 match, got %d", len(result.matches))
+	}
+
+	stale := searchResultMsg{seq: result.seq - 1}
+	updated, _ = model.Update(stale)
+	if len(updated.(Model).SearchResults.Items()) != 0 {
+		t.Error("Expected stale search results to be ignored")
+	}
+
+	updated, _ = model.Update(result)
+	model = updated.(Model)
+	if len(model.SearchResults.Items()) != 1 {
+		t.Fatalf("Expected 1 result item, got %d", len(model.SearchResults.Items()))
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model = updated.(Model)
+	if model.CurrentNote == nil || model.CurrentNote.Path() != "ideas.md" {
+		t.Fatal("Expected ideas.md to be opened")
+	}
+	if model.NoteContent.Line() != 2 {
+		t.Errorf("Expected cursor on line index 2, got %d", model.NoteContent.Line())
+	}
+}
+
+func TestModel_SaveUpdatesIndex(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	model := InitialModel(testConfig(tmpDir))
+	if err := model.OpenOrCreateFile("indexed.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	model.NoteContent.SetValue("quarterly roadmap")
+	model.SaveNote()
+
+	matches, err := model.Index.Search(context.Background(), model.Store, "roadmap")
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Path != "indexed.md" {
+		t.Errorf("Expected saved note to be indexed, got %v", matches)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, search.IndexFile)); err != nil {
+		t.Errorf("Expected index to be persisted in the notes directory: %v", err)
+	}
+}
+
+func TestModel_History(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	model := InitialModel(testConfig(tmpDir))
+	model.SetStore(store.NewMemStore())
+	if err := model.OpenOrCreateFile("versioned.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+
+	model.NoteContent.SetValue("first draft")
+	if err := model.WriteNote(); err != nil {
+		t.Fatalf("WriteNote failed: %v", err)
+	}
+	model.NoteContent.SetValue("second draft")
+	if err := model.WriteNote(); err != nil {
+		t.Fatalf("WriteNote failed: %v", err)
+	}
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m := updated.(Model)
+	if !m.HistoryVisible {
+		t.Fatal("Expected ctrl+r to open the history screen")
+	}
+	if len(m.HistoryList.Items()) != 2 {
+		t.Fatalf("Expected 2 versions, got %d", len(m.HistoryList.Items()))
+	}
+
+	t.Run("restore older version", func(t *testing.T) {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEnter})
+		restored := updated.(Model)
+
+		if restored.HistoryVisible {
+			t.Error("Expected history screen to close after restoring")
+		}
+		if restored.NoteContent.Value() != "first draft" {
+			t.Errorf("Expected restored content 'first draft', got '%s'", restored.NoteContent.Value())
+		}
+		if !restored.Dirty {
+			t.Error("Expected restored note to be marked dirty")
+		}
+	})
+
+	t.Run("esc closes history", func(t *testing.T) {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+		if updated.(Model).HistoryVisible {
+			t.Error("Expected esc to close the history screen")
+		}
+		if updated.(Model).CurrentNote == nil {
+			t.Error("Expected note to stay open after closing history")
+		}
+	})
+}
+
+func TestModel_Trash(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	model := InitialModel(testConfig(tmpDir))
+	mem := store.NewMemStore()
+	mem.Write("keep.md", []byte("keep"))
+	mem.MakeDir("work")
+	mem.Write("work/plan.md", []byte("plan"))
+	model.SetStore(mem)
+
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.Update(msg)
+		return updated.(Model)
+	}
+
+	model = press(model, tea.KeyMsg{Type: tea.KeyCtrlL})
+	model = press(model, tea.KeyMsg{Type: tea.KeyEnter})
+	if model.CurrentDir != "work" {
+		t.Fatalf("Expected to be inside 'work', got '%s'", model.CurrentDir)
+	}
+	model.List.Select(1)
+	model = press(model, tea.KeyMsg{Type: tea.KeyDelete})
+
+	if ok, _ := store.Exists(mem, "work/plan.md"); ok {
+		t.Fatal("Expected note to be moved out of its folder")
+	}
+	entries, _ := model.Trash.List()
+	if len(entries) != 1 || entries[0].Path != "work/plan.md" {
+		t.Fatalf("Expected note in trash with its original path, got %+v", entries)
+	}
+
+	t.Run("delete while filtering edits the filter", func(t *testing.T) {
+		m := press(model, tea.KeyMsg{Type: tea.KeyEsc})
+		m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+		m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+		if m.List.FilterState() != list.Filtering {
+			t.Fatal("Expected list to be filtering")
+		}
+		m = press(m, tea.KeyMsg{Type: tea.KeyBackspace})
+		if ok, _ := store.Exists(mem, "keep.md"); !ok {
+			t.Error("Expected backspace in the filter not to delete a note")
+		}
+	})
+
+	t.Run("restore from trash view", func(t *testing.T) {
+		m := press(model, tea.KeyMsg{Type: tea.KeyCtrlX})
+		if !m.TrashVisible || len(m.TrashList.Items()) != 1 {
+			t.Fatalf("Expected trash view with 1 note, visible %v", m.TrashVisible)
+		}
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if content, err := mem.Read("work/plan.md"); err != nil || string(content) != "plan" {
+			t.Errorf("Expected note restored to 'work/plan.md', got %q (%v)", content, err)
+		}
+		if len(m.TrashList.Items()) != 0 {
+			t.Error("Expected trash to be empty after restore")
+		}
+		m = press(m, tea.KeyMsg{Type: tea.KeyEsc})
+		if m.TrashVisible || !m.ListVisible {
+			t.Error("Expected esc to return to the list")
+		}
+	})
+
+	t.Run("purge needs a second press", func(t *testing.T) {
+		entries, _ := model.Trash.List()
+		if len(entries) == 0 {
+			model.Trash.Move("work/plan.md")
+		}
+		m := press(model, tea.KeyMsg{Type: tea.KeyCtrlX})
+		m = press(m, tea.KeyMsg{Type: tea.KeyDelete})
+		if entries, _ := m.Trash.List(); len(entries) != 1 {
+			t.Fatal("Expected first press to only ask for confirmation")
+		}
+		m = press(m, tea.KeyMsg{Type: tea.KeyDelete})
+		if entries, _ := m.Trash.List(); len(entries) != 0 {
+			t.Error("Expected second press to purge the note")
+		}
+		if ok, _ := store.Exists(mem, "work/plan.md"); ok {
+			t.Error("Expected purged note not to come back")
+		}
+	})
+}
+
+func TestModel_Rename(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	model := InitialModel(testConfig(tmpDir))
+	mem := store.NewMemStore()
+	mem.Write("plan.md", []byte("the plan"))
+	mem.Write("taken.md", []byte("taken"))
+	mem.MakeDir("work")
+	mem.Write("work/index.md", []byte("See [[plan]] and [plan](../plan.md)"))
+	model.SetStore(mem)
+	model.History.Record("plan.md", []byte("the plan"))
+
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.Update(msg)
+		return updated.(Model)
+	}
+
+	model = press(model, tea.KeyMsg{Type: tea.KeyCtrlL})
+	model.List.Select(1) // work/, plan.md, taken.md
+	model = press(model, tea.KeyMsg{Type: tea.KeyCtrlE})
+	if !model.CreateFileInputVisible || model.InputMode != InputRename {
+		t.Fatal("Expected rename prompt")
+	}
+	if model.NewFileInput.Value() != "plan" {
+		t.Errorf("Expected prompt prefilled with 'plan', got '%s'", model.NewFileInput.Value())
+	}
+
+	t.Run("rejects collisions", func(t *testing.T) {
+		m := model
+		m.NewFileInput.SetValue("taken")
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+		if !m.CreateFileInputVisible || m.ErrMsg == "" {
+			t.Error("Expected an error and the prompt to stay open")
+		}
+		if content, _ := mem.Read("taken.md"); string(content) != "taken" {
+			t.Error("Expected existing note to be left alone")
+		}
+	})
+
+	t.Run("renames and updates links", func(t *testing.T) {
+		m := model
+		m.NewFileInput.SetValue("roadmap")
+		m = press(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+		if ok, _ := store.Exists(mem, "plan.md"); ok {
+			t.Error("Expected old name to be gone")
+		}
+		if content, _ := mem.Read("roadmap.md"); string(content) != "the plan" {
+			t.Errorf("Expected renamed note content, got '%s'", string(content))
+		}
+		content, _ := mem.Read("work/index.md")
+		if string(content) != "See [[roadmap]] and [roadmap](../roadmap.md)" {
+			t.Errorf("Expected links to be updated, got '%s'", string(content))
+		}
+		if versions, _ := m.History.Versions("roadmap.md"); len(versions) != 1 {
+			t.Errorf("Expected history to follow the note, got %d versions", len(versions))
+		}
+		if !m.ListVisible || m.CreateFileInputVisible {
+			t.Error("Expected to return to the list after renaming")
 		}
 	})
 }
+
+func TestModel_RenameTitle(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	model := InitialModel(testConfig(tmpDir))
+	mem := store.NewMemStore()
+	mem.Write("Q&A- plans.md", []byte("---\ntitle: 'Q&A: plans'\ncreated: 2024-01-15T10:30:00Z\n---\n\nbody"))
+	model.SetStore(mem)
+
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.Update(msg)
+		return updated.(Model)
+	}
+	rename := func(m Model, title string) Model {
+		m = press(m, tea.KeyMsg{Type: tea.KeyCtrlL})
+		m = press(m, tea.KeyMsg{Type: tea.KeyCtrlE})
+		m.NewFileInput.SetValue(title)
+		return press(m, tea.KeyMsg{Type: tea.KeyEnter})
+	}
+
+	model = press(model, tea.KeyMsg{Type: tea.KeyCtrlL})
+	model = press(model, tea.KeyMsg{Type: tea.KeyCtrlE})
+	if model.NewFileInput.Value() != "Q&A: plans" {
+		t.Errorf("Expected the prompt prefilled with the title, got %q", model.NewFileInput.Value())
+	}
+
+	model = rename(model, "Why")
+	content, err := mem.Read("Why.md")
+	if err != nil {
+		t.Fatalf("Expected the note to be renamed: %v", err)
+	}
+	if string(content) != "---\ncreated: 2024-01-15T10:30:00Z\n---\n\nbody" {
+		t.Errorf("Expected the old title to be dropped, got %q", content)
+	}
+	if title := model.List.Items()[0].(file.Note).Title(); title != "Why" {
+		t.Errorf("Expected the list to show the new name, got %q", title)
+	}
+
+	model = rename(model, "Why: now")
+	content, err = mem.Read("Why- now.md")
+	if err != nil {
+		t.Fatalf("Expected the note to be renamed: %v", err)
+	}
+	if !strings.Contains(string(content), "title: 'Why: now'") {
+		t.Errorf("Expected the new title in the front matter, got %q", content)
+	}
+
+	// A title of the user's own is kept, and the prompt names the file.
+	mem.Delete("Why- now.md")
+	mem.Write("standup.md", []byte("---\ntitle: Daily standup\n---\n\nbody"))
+	model.refreshList()
+	model = press(model, tea.KeyMsg{Type: tea.KeyCtrlE})
+	if model.NewFileInput.Value() != "standup" {
+		t.Errorf("Expected the prompt prefilled with the file name, got %q", model.NewFileInput.Value())
+	}
+	model.NewFileInput.SetValue("meeting")
+	model = press(model, tea.KeyMsg{Type: tea.KeyEnter})
+	content, err = mem.Read("meeting.md")
+	if err != nil {
+		t.Fatalf("Expected the note to be renamed: %v (%s)", err, model.ErrMsg)
+	}
+	if string(content) != "---\ntitle: Daily standup\n---\n\nbody" {
+		t.Errorf("Expected the title to be kept, got %q", content)
+	}
+}
+
+func TestModel_CreateNoteNames(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	model := InitialModel(testConfig(tmpDir))
+	mem := store.NewMemStore()
+	model.SetStore(mem)
+
+	create := func(name string) Model {
+		updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+		m := updated.(Model)
+		m.NewFileInput.SetValue(name)
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		return updated.(Model)
+	}
+
+	t.Run("unsafe characters keep the title", func(t *testing.T) {
+		m := create("Q1: goals")
+		if m.CurrentNote == nil || m.CurrentNote.Path() != "Q1- goals.md" {
+			t.Fatalf("Expected 'Q1- goals.md' to be open, got %v", m.CurrentNote)
+		}
+		if m.CurrentNote.Title() != "Q1: goals" {
+			t.Errorf("Expected title 'Q1: goals', got '%s'", m.CurrentNote.Title())
+		}
+	})
+
+	t.Run("traversal is rejected", func(t *testing.T) {
+		m := create("../../.bashrc")
+		if m.CurrentNote != nil {
+			t.Fatalf("Expected no note to be opened, got %s", m.CurrentNote.Path())
+		}
+		if !strings.Contains(m.ErrMsg, "invalid name") {
+			t.Errorf("Expected an invalid name error, got '%s'", m.ErrMsg)
+		}
+		if !m.CreateFileInputVisible {
+			t.Error("Expected the prompt to stay open")
+		}
+	})
+}
+
+func TestModel_ExternalChanges(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	newModel := func(t *testing.T) (Model, *store.MemStore) {
+		model := InitialModel(testConfig(tmpDir))
+		mem := store.NewMemStore()
+		mem.Write("shared.md", []byte("original"))
+		model.SetStore(mem)
+		if err := model.OpenOrCreateFile("shared.md"); err != nil {
+			t.Fatalf("Failed to open file: %v", err)
+		}
+		return model, mem
+	}
+	watch := func(m Model) Model {
+		updated, _ := m.Update(watchMsg(time.Now()))
+		return updated.(Model)
+	}
+	press := func(m Model, key string) Model {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(Model)
+	}
+
+	t.Run("clean note is reloaded", func(t *testing.T) {
+		m, mem := newModel(t)
+		mem.Write("shared.md", []byte("edited elsewhere"))
+		m = watch(m)
+		if m.NoteContent.Value() != "edited elsewhere" {
+			t.Errorf("Expected note to be reloaded, got '%s'", m.NoteContent.Value())
+		}
+		if m.Conflict {
+			t.Error("Expected no conflict without local edits")
+		}
+	})
+
+	t.Run("save does not overwrite external change", func(t *testing.T) {
+		m, mem := newModel(t)
+		m.NoteContent.SetValue("mine")
+		m.markDirty()
+		mem.Write("shared.md", []byte("theirs"))
+
+		if err := m.SaveNote(); err == nil {
+			t.Fatal("Expected SaveNote to report the conflict")
+		}
+		if !m.Conflict || m.CurrentNote == nil {
+			t.Fatal("Expected conflict prompt with the note still open")
+		}
+		if content, _ := mem.Read("shared.md"); string(content) != "theirs" {
+			t.Errorf("Expected disk version to be kept, got '%s'", string(content))
+		}
+		if !strings.Contains(m.View(), "changed outside Totion") {
+			t.Error("Expected the conflict prompt in the view")
+		}
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		m, mem := newModel(t)
+		m.NoteContent.SetValue("mine")
+		m.markDirty()
+		mem.Write("shared.md", []byte("theirs"))
+		m = watch(m)
+		if !m.Conflict {
+			t.Fatal("Expected a conflict for a dirty note")
+		}
+		m = press(m, "r")
+		if m.Conflict || m.NoteContent.Value() != "theirs" {
+			t.Errorf("Expected disk version after reload, got '%s'", m.NoteContent.Value())
+		}
+	})
+
+	t.Run("overwrite", func(t *testing.T) {
+		m, mem := newModel(t)
+		m.NoteContent.SetValue("mine")
+		m.markDirty()
+		mem.Write("shared.md", []byte("theirs"))
+		m = press(watch(m), "o")
+		if content, _ := mem.Read("shared.md"); string(content) != "mine" {
+			t.Errorf("Expected our version on disk, got '%s'", string(content))
+		}
+		if m.Conflict || m.Dirty {
+			t.Error("Expected a clean note after overwriting")
+		}
+	})
+
+	t.Run("save as copy", func(t *testing.T) {
+		m, mem := newModel(t)
+		m.NoteContent.SetValue("mine")
+		m.markDirty()
+		mem.Write("shared.md", []byte("theirs"))
+		m = press(watch(m), "c")
+		if content, _ := mem.Read("shared.md"); string(content) != "theirs" {
+			t.Errorf("Expected disk version to be kept, got '%s'", string(content))
+		}
+		if content, _ := mem.Read("shared (conflict copy).md"); string(content) != "mine" {
+			t.Errorf("Expected our version in the copy, got '%s'", string(content))
+		}
+		if m.CurrentNote.Path() != "shared (conflict copy).md" {
+			t.Errorf("Expected to continue editing the copy, got '%s'", m.CurrentNote.Path())
+		}
+	})
+
+	t.Run("list picks up new files", func(t *testing.T) {
+		m, mem := newModel(t)
+		m.SaveNote()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyCtrlL})
+		m = updated.(Model)
+		if len(m.List.Items()) != 1 {
+			t.Fatalf("Expected 1 note, got %d", len(m.List.Items()))
+		}
+		mem.Write("new.md", []byte("new"))
+		m = watch(m)
+		if len(m.List.Items()) != 2 {
+			t.Errorf("Expected list to show the new note, got %d items", len(m.List.Items()))
+		}
+	})
+}
+
+func TestModel_Locking(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	mem := store.NewMemStore()
+	mem.Write("shared.md", []byte("shared"))
+	instance := func(pid int) Model {
+		m := InitialModel(testConfig(tmpDir))
+		m.SetStore(mem)
+		m.Locker.PID = pid
+		m.Locker.Alive = func(int) bool { return true }
+		return m
+	}
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.Update(msg)
+		return updated.(Model)
+	}
+
+	first := instance(100)
+	if err := first.OpenOrCreateFile("shared.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	if first.ReadOnly || first.LockOwner != nil {
+		t.Fatal("Expected the first instance to get the lock")
+	}
+
+	second := instance(200)
+	second.OpenOrCreateFile("shared.md")
+	if !second.ReadOnly || second.LockOwner == nil || second.LockOwner.PID != 100 {
+		t.Fatalf("Expected the second instance to see the lock of pid 100, got %+v", second.LockOwner)
+	}
+	if !strings.Contains(second.View(), "open in another Totion") {
+		t.Error("Expected the lock prompt in the view")
+	}
+
+	t.Run("read-only", func(t *testing.T) {
+		m := press(second, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+		if m.LockOwner != nil || !m.ReadOnly {
+			t.Fatal("Expected to continue read-only")
+		}
+		m = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+		if m.NoteContent.Value() != "shared" || m.Dirty {
+			t.Errorf("Expected edits to be refused, got '%s'", m.NoteContent.Value())
+		}
+		if err := m.SaveNote(); err != nil || m.CurrentNote != nil {
+			t.Errorf("Expected a read-only note to close without saving, got %v", err)
+		}
+		if o, ok, _ := m.Locker.Owner("shared.md"); !ok || o.PID != 100 {
+			t.Error("Expected the lock of the first instance to be kept")
+		}
+	})
+
+	t.Run("take over", func(t *testing.T) {
+		m := press(second, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+		if m.ReadOnly || m.LockOwner != nil {
+			t.Fatal("Expected to edit after taking over")
+		}
+		m.NoteContent.SetValue("from second")
+		if err := m.WriteNote(); err != nil {
+			t.Fatalf("WriteNote failed: %v", err)
+		}
+
+		f := first
+		f.NoteContent.SetValue("from first")
+		if err := f.WriteNote(); err == nil {
+			t.Error("Expected the first instance to notice it lost the lock")
+		}
+		if !f.ReadOnly || f.LockOwner == nil {
+			t.Error("Expected the first instance to turn read-only")
+		}
+		if content, _ := mem.Read("shared.md"); string(content) != "from second" {
+			t.Errorf("Expected the second instance's save to win, got '%s'", string(content))
+		}
+
+		m.SaveNote()
+		if _, ok, _ := m.Locker.Owner("shared.md"); ok {
+			t.Error("Expected closing the note to release the lock")
+		}
+	})
+
+	t.Run("lost lock with unsaved edits", func(t *testing.T) {
+		a := instance(300)
+		a.OpenOrCreateFile("shared.md")
+		b := instance(400)
+		b.OpenOrCreateFile("shared.md")
+		b = press(b, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+		defer b.SaveNote()
+
+		a = press(a, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("unsaved")})
+		if err := a.WriteNote(); err == nil || a.LockOwner == nil {
+			t.Fatal("Expected the save to find the lock taken over")
+		}
+		if !strings.Contains(a.View(), "before your edits were saved") {
+			t.Error("Expected the lock prompt to offer a copy")
+		}
+		a = press(a, tea.KeyMsg{Type: tea.KeyEsc})
+		if a.CurrentNote == nil || a.LockOwner == nil {
+			t.Fatal("Expected Esc not to close a note with unsaved edits")
+		}
+
+		a = press(a, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+		a = press(a, tea.KeyMsg{Type: tea.KeyEsc})
+		if a.CurrentNote == nil || !strings.Contains(a.ErrMsg, "as a copy") {
+			t.Fatalf("Expected closing the read-only note to be refused, got %q", a.ErrMsg)
+		}
+
+		a = press(a, tea.KeyMsg{Type: tea.KeyCtrlS})
+		if a.CurrentNote.Path() != "shared (conflict copy).md" || a.ReadOnly {
+			t.Fatalf("Expected to continue in a copy, got %s (%s)", a.CurrentNote.Path(), a.ErrMsg)
+		}
+		if content, _ := mem.Read("shared (conflict copy).md"); !strings.Contains(string(content), "unsaved") {
+			t.Errorf("Expected the edits in the copy, got %q", content)
+		}
+		if content, _ := mem.Read("shared.md"); strings.Contains(string(content), "unsaved") {
+			t.Error("Expected the note of the other instance to be left alone")
+		}
+		a.SaveNote()
+	})
+}
+
+func TestModel_Config(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	cfg := testConfig(tmpDir)
+	cfg.Editor.NameLimit = 80
+	cfg.Editor.AutoSaveIdle = 0
+	cfg.Autocomplete.Enabled = true
+	cfg.Autocomplete.APIKey = "test-key"
+	cfg.Autocomplete.Delay = 2
+	cfg.Trash.RetentionDays = 7
+
+	model := InitialModel(cfg)
+	if !model.AutoCompleteEnabled {
+		t.Error("Expected autocomplete to start enabled")
+	}
+	if model.NewFileInput.CharLimit != 80 {
+		t.Errorf("Expected name limit 80, got %d", model.NewFileInput.CharLimit)
+	}
+	if model.AutoSaveIdle != 0 || model.AutoSaveInterval != cfg.Editor.AutoSaveInterval {
+		t.Errorf("Expected autosave settings from config, got %s / %s", model.AutoSaveIdle, model.AutoSaveInterval)
+	}
+	if model.Trash.RetainFor != 7*24*time.Hour {
+		t.Errorf("Expected trash retention of 7 days, got %s", model.Trash.RetainFor)
+	}
+
+	if err := model.OpenOrCreateFile("delay.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	tick := func(m Model) (Model, tea.Cmd) {
+		updated, cmd := m.Update(tickMsg{gen: m.TickGen, time: time.Now()})
+		return updated.(Model), cmd
+	}
+	model, _ = tick(model)
+	if model.SuggesTimeCount != 1 {
+		t.Fatalf("Expected one tick counted, got %d", model.SuggesTimeCount)
+	}
+	model, cmd := tick(model)
+	if model.SuggesTimeCount != 2 || cmd == nil {
+		t.Errorf("Expected a suggestion request after the configured delay")
+	}
+}
+
+func TestModel_NoAPIKey(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	cfg := testConfig(tmpDir)
+	cfg.Autocomplete.Enabled = true
+	model := InitialModel(cfg)
+	if model.Client != nil || model.AutoCompleteEnabled {
+		t.Fatal("Expected AI to be disabled without an API key")
+	}
+	if !strings.Contains(model.AIDisabled, "GEMINI_API_KEY") {
+		t.Errorf("Expected the reason to mention GEMINI_API_KEY, got %q", model.AIDisabled)
+	}
+
+	if err := model.OpenOrCreateFile("ai.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	model = updated.(Model)
+	if model.AutoCompleteEnabled {
+		t.Error("Expected Ctrl+T not to enable autocomplete without a client")
+	}
+	if !strings.Contains(model.ErrMsg, "AI is disabled") {
+		t.Errorf("Expected the reason in the status, got %q", model.ErrMsg)
+	}
+	if view := model.View(); !strings.Contains(view, "AI disabled") {
+		t.Error("Expected the view to show that AI is disabled")
+	}
+}
+
+func TestModel_KeyBindings(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	cfg := testConfig(tmpDir)
+	cfg.Keys["new_note"] = []string{"alt+n"}
+	cfg.Keys["list"] = []string{"ctrl+q"}
+	model := InitialModel(cfg)
+
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	if updated.(Model).CreateFileInputVisible {
+		t.Error("Expected Ctrl+N to do nothing once remapped")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n"), Alt: true})
+	if !updated.(Model).CreateFileInputVisible {
+		t.Error("Expected Alt+N to open the new note input")
+	}
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyCtrlQ})
+	if !updated.(Model).ListVisible {
+		t.Error("Expected Ctrl+Q to open the notes list")
+	}
+
+	view := model.View()
+	if !strings.Contains(view, "Alt+N: New Note") || strings.Contains(view, "Ctrl+N") {
+		t.Error("Expected the help line to show the remapped keys")
+	}
+}
+
+func TestModel_EditorKeys(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	createTestNoteFile(t, tmpDir, "plan", "first line\nsecond line")
+
+	model := InitialModel(testConfig(tmpDir))
+	if err := model.OpenOrCreateFile("plan.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(Model)
+	}
+
+	model.gotoLine(0)
+	model.NoteContent.SetCursor(5)
+	press(tea.KeyMsg{Type: tea.KeyCtrlB})
+	press(tea.KeyMsg{Type: tea.KeyCtrlK})
+	if model.SearchVisible || model.BacklinksVisible || !strings.HasPrefix(model.NoteContent.Value(), "firs\n") {
+		t.Errorf("Expected Ctrl+B and Ctrl+K to edit the note, got %q", model.NoteContent.Value())
+	}
+	model.gotoLine(1)
+	press(tea.KeyMsg{Type: tea.KeyCtrlP})
+	if model.Layout != LayoutEditor || model.NoteContent.Line() != 0 {
+		t.Errorf("Expected Ctrl+P to move up a line, got layout %d on line %d", model.Layout, model.NoteContent.Line())
+	}
+}
+
+func TestModel_InvalidKeyBindings(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+
+	cfg := testConfig(tmpDir)
+	cfg.Keys["rename"] = []string{"ctrl+n"}
+	model := InitialModel(cfg)
+	if !strings.Contains(model.ErrMsg, "bound to both") {
+		t.Errorf("Expected the conflict to be reported, got %q", model.ErrMsg)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlE}, model.Keys.Rename) {
+		t.Error("Expected the default bindings to be used")
+	}
+}
+
+func TestModel_Preview(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+	createTestNoteFile(t, tmpDir, "doc", "# Title\n\nSome **bold** text.\n\n- one\n- two\n")
+
+	model := InitialModel(testConfig(tmpDir))
+	if err := model.OpenOrCreateFile("doc.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p"), Alt: true})
+	if model.Layout != LayoutPreview {
+		t.Fatal("Expected Alt+P to open the preview")
+	}
+	view := model.View()
+	if strings.Contains(view, "**bold**") || !strings.Contains(view, "bold") {
+		t.Error("Expected the preview to render the markdown")
+	}
+	if !strings.Contains(view, "Scroll") {
+		t.Error("Expected the preview help line")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("z")})
+	if strings.Contains(model.NoteContent.Value(), "z") || model.Dirty {
+		t.Error("Expected typing in the preview not to edit the note")
+	}
+
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 40, Height: 30})
+	model = updated.(Model)
+	for _, line := range strings.Split(model.PreviewView.View(), "\n") {
+		if w := lipgloss.Width(line); w > model.PreviewView.Width {
+			t.Errorf("Expected preview lines to fit %d columns, got %d", model.PreviewView.Width, w)
+		}
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.Layout != LayoutEditor || model.CurrentNote == nil {
+		t.Error("Expected Esc to return to the editor")
+	}
+}
+
+func TestModel_SplitLayout(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+	var content strings.Builder
+	content.WriteString("# Title\n\nSome **bold** text.\n")
+	for i := 0; i < 40; i++ {
+		fmt.Fprintf(&content, "\nParagraph %d.\n", i)
+	}
+	createTestNoteFile(t, tmpDir, "doc", content.String())
+
+	model := InitialModel(testConfig(tmpDir))
+	if err := model.OpenOrCreateFile("doc.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	update := func(msg tea.Msg) tea.Cmd {
+		updated, cmd := model.Update(msg)
+		model = updated.(Model)
+		return cmd
+	}
+	update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p"), Alt: true})
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p"), Alt: true})
+	if model.Layout != LayoutSplit {
+		t.Fatalf("Expected Alt+P twice to split the view, got layout %d", model.Layout)
+	}
+	h, _ := styles.DocStyle.GetFrameSize()
+	if w := model.NoteContent.Width() + model.PreviewView.Width + styles.PaneStyle.GetHorizontalFrameSize(); w > 120-h {
+		t.Errorf("Expected both panes to fit %d columns, got %d", 120-h, w)
+	}
+	view := model.View()
+	if !strings.Contains(view, "**bold**") || !strings.Contains(view, "Paragraph") {
+		t.Error("Expected the editor and the preview side by side")
+	}
+
+	// The cursor starts at the end of the note, so the preview follows it
+	// to the bottom and back to the top.
+	if model.PreviewView.YOffset == 0 {
+		t.Error("Expected the preview to scroll with the editor cursor")
+	}
+	update(tea.KeyMsg{Type: tea.KeyCtrlHome})
+	if model.PreviewView.YOffset != 0 {
+		t.Errorf("Expected the preview at the top with the cursor, got offset %d", model.PreviewView.YOffset)
+	}
+
+	gen := model.PreviewGen
+	if cmd := update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Zebra ")}); cmd == nil || model.PreviewGen == gen {
+		t.Fatal("Expected typing to schedule a preview render")
+	}
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("crossing ")})
+	update(previewMsg{gen: model.PreviewGen - 1})
+	if strings.Contains(model.PreviewView.View(), "Zebra") {
+		t.Error("Expected a stale render request to be ignored")
+	}
+	update(previewMsg{gen: model.PreviewGen})
+	if !strings.Contains(model.PreviewView.View(), "Zebra crossing") {
+		t.Error("Expected the preview to render the typed text")
+	}
+
+	update(tea.WindowSizeMsg{Width: 60, Height: 40})
+	if model.NoteContent.Width() < 60-h-2 {
+		t.Errorf("Expected the editor to take the full width when narrow, got %d", model.NoteContent.Width())
+	}
+	if strings.Contains(model.View(), styles.PaneStyle.GetBorderStyle().Left) {
+		t.Error("Expected only the editor on a narrow terminal")
+	}
+
+	update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p"), Alt: true})
+	if model.Layout != LayoutEditor {
+		t.Error("Expected Alt+P to cycle back to the editor")
+	}
+}
+
+func TestModel_FollowLinkByTitle(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	createTestNoteFile(t, tmpDir, "index", "[[Q&A: plans]]")
+	createTestNoteFile(t, tmpDir, "Q&A- plans", "---\ntitle: 'Q&A: plans'\n---\n")
+
+	model := InitialModel(testConfig(tmpDir))
+	if err := model.OpenOrCreateFile("index.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	model.NoteContent.SetCursor(2)
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyCtrlCloseBracket})
+	model = updated.(Model)
+	if model.CurrentNote.Path() != "Q&A- plans.md" {
+		t.Errorf("Expected the link to open the note titled Q&A: plans, got %s (%s)", model.CurrentNote.Path(), model.ErrMsg)
+	}
+	if notes, _ := filepath.Glob(filepath.Join(tmpDir, "*.md")); len(notes) != 2 {
+		t.Errorf("Expected no note to be created, got %v", notes)
+	}
+}
+
+func TestModel_FollowLink(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+	createTestNoteFile(t, tmpDir, "index", "See [[Plan|the plan]] and [[work/Ideas]].")
+	createTestNoteFile(t, tmpDir, "plan", "# Plan")
+
+	model := InitialModel(testConfig(tmpDir))
+	if err := model.OpenOrCreateFile("index.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(Model)
+	}
+	follow := tea.KeyMsg{Type: tea.KeyCtrlCloseBracket}
+	back := tea.KeyMsg{Type: tea.KeyCtrlO}
+	forward := tea.KeyMsg{Type: tea.KeyCtrlY}
+
+	model.gotoLine(0)
+	press(follow)
+	if model.CurrentNote.Path() != "index.md" || !strings.Contains(model.ErrMsg, "no [[link]]") {
+		t.Errorf("Expected following without a link to fail, got %q", model.ErrMsg)
+	}
+
+	model.NoteContent.CursorEnd()
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(" Done")})
+	model.NoteContent.SetCursor(8)
+	if !strings.Contains(model.View(), "→ Plan") {
+		t.Error("Expected the link under the cursor to be shown")
+	}
+	press(follow)
+	if model.CurrentNote.Path() != "plan.md" {
+		t.Fatalf("Expected to follow the link to plan.md, got %s (%s)", model.CurrentNote.Path(), model.ErrMsg)
+	}
+	content, err := model.Store.Read("index.md")
+	if err != nil || !strings.Contains(string(content), "Done") {
+		t.Errorf("Expected the note to be saved before following, got %q", content)
+	}
+
+	press(back)
+	if model.CurrentNote.Path() != "index.md" {
+		t.Fatalf("Expected to go back to index.md, got %s", model.CurrentNote.Path())
+	}
+	press(forward)
+	if model.CurrentNote.Path() != "plan.md" {
+		t.Fatalf("Expected to go forward to plan.md, got %s", model.CurrentNote.Path())
+	}
+	press(back)
+
+	// A link to a missing note creates it in the folder it names.
+	line := model.NoteContent.Value()
+	model.NoteContent.SetCursor(strings.Index(line, "work/"))
+	press(follow)
+	if model.CurrentNote.Path() != "work/Ideas.md" {
+		t.Fatalf("Expected the missing note to be created, got %s (%s)", model.CurrentNote.Path(), model.ErrMsg)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "work", "Ideas.md")); err != nil {
+		t.Errorf("Expected work/Ideas.md on disk: %v", err)
+	}
+	press(forward)
+	if !strings.Contains(model.ErrMsg, "no note to go forward to") {
+		t.Errorf("Expected following a link to clear the forward stack, got %q", model.ErrMsg)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.LinkBack != nil || model.LinkForward != nil {
+		t.Error("Expected closing the note to forget the visited notes")
+	}
+}
+
+func TestModel_Backlinks(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+	createTestNoteFile(t, tmpDir, "plan", "# Plan")
+	createTestNoteFile(t, tmpDir, "index", "Intro\nSee [[Plan]] for details.")
+	createTestNoteFile(t, tmpDir, "other", "The [plan](plan.md) again.")
+
+	model := InitialModel(testConfig(tmpDir))
+	if err := model.OpenOrCreateFile("plan.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(Model)
+	}
+
+	updated, _ := model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model = updated.(Model)
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i"), Alt: true})
+	if !model.BacklinksVisible {
+		t.Fatal("Expected Alt+I to open the backlinks")
+	}
+	if n := len(model.BacklinksList.Items()); n != 2 {
+		t.Fatalf("Expected 2 backlinks, got %d", n)
+	}
+	if view := model.View(); !strings.Contains(view, "index.md:2") || !strings.Contains(view, "for details") {
+		t.Error("Expected the backlinks with their line of context")
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.BacklinksVisible || model.CurrentNote.Path() != "index.md" || model.NoteContent.Line() != 1 {
+		t.Fatalf("Expected index.md to open at the link, got %v line %d", model.CurrentNote, model.NoteContent.Line())
+	}
+
+	// Saving updates the links without reading the notes again.
+	model.NoteContent.SetValue("No more links.")
+	press(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if refs := model.Links.Links("index.md"); len(refs) != 0 {
+		t.Errorf("Expected the saved note's links to be updated, got %v", refs)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyCtrlO})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("i"), Alt: true})
+	if n := len(model.BacklinksList.Items()); n != 1 {
+		t.Errorf("Expected 1 backlink after the edit, got %d", n)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.BacklinksVisible || model.CurrentNote == nil {
+		t.Error("Expected Esc to return to the note")
+	}
+}
+
+func TestModel_Graph(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	defer os.RemoveAll(tmpDir)
+	createTestNoteFile(t, tmpDir, "plan", "See [[ideas]] and [[index]].")
+	createTestNoteFile(t, tmpDir, "index", "[[plan]]")
+
+	model := InitialModel(testConfig(tmpDir))
+	if err := model.OpenOrCreateFile("plan.md"); err != nil {
+		t.Fatalf("Failed to open file: %v", err)
+	}
+	updated, _ := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g"), Alt: true})
+	model = updated.(Model)
+	if !model.GraphVisible {
+		t.Fatal("Expected Alt+G to open the local graph")
+	}
+	view := model.View()
+	if !strings.Contains(view, "├── ↔ index.md") || !strings.Contains(view, "→ ideas (missing)") {
+		t.Errorf("Expected the neighbourhood tree, got:\n%s", view)
+	}
+
+	updated, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	model = updated.(Model)
+	if model.GraphVisible || model.CurrentNote == nil {
+		t.Error("Expected Esc to return to the note")
+	}
+}
+
+func TestModel_Journal(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	model := InitialModel(testConfig(tmpDir))
+	mem := store.NewMemStore()
+	mem.Write("plan.md", []byte("plan"))
+	mem.MakeDir("journal")
+	mem.Write("journal/2000-01-01.md", []byte("first"))
+	mem.Write("journal/2000-01-05.md", []byte("second"))
+	mem.Write("journal/ideas.md", []byte("not a day"))
+	model.SetStore(mem)
+
+	press := func(m Model, msg tea.KeyMsg) Model {
+		updated, _ := m.Update(msg)
+		return updated.(Model)
+	}
+	alt := func(r rune) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}, Alt: true}
+	}
+
+	now := time.Now()
+	today := "journal/" + now.Format("2006-01-02") + ".md"
+	model = press(model, alt('t'))
+	if model.CurrentNote == nil || model.CurrentNote.Path() != today {
+		t.Fatalf("Expected Alt+T to open %s, got %+v (%s)", today, model.CurrentNote, model.ErrMsg)
+	}
+	data, err := mem.Read(today)
+	if err != nil {
+		t.Fatalf("Expected today's note to be written: %v", err)
+	}
+	if !strings.Contains(string(data), "# "+now.Format("Monday, 2 January 2006")) {
+		t.Errorf("Expected the default heading, got %q", data)
+	}
+
+	// The days around it are opened one by one, written when missing.
+	yesterday := now.AddDate(0, 0, -1)
+	model = press(model, alt(','))
+	if model.CurrentNote.Path() != "journal/"+yesterday.Format("2006-01-02")+".md" {
+		t.Fatalf("Expected Alt+, to open yesterday's note, got %s (%s)", model.CurrentNote.Path(), model.ErrMsg)
+	}
+	if data, _ := mem.Read(model.CurrentNote.Path()); !strings.Contains(string(data), "# "+yesterday.Format("Monday, 2 January 2006")) {
+		t.Errorf("Expected yesterday's note from the template, got %q", data)
+	}
+	model = press(model, alt('.'))
+	model = press(model, alt('.'))
+	tomorrow := "journal/" + now.AddDate(0, 0, 1).Format("2006-01-02") + ".md"
+	if model.CurrentNote.Path() != tomorrow {
+		t.Errorf("Expected Alt+. twice to reach tomorrow's note, got %s (%s)", model.CurrentNote.Path(), model.ErrMsg)
+	}
+	if err := model.OpenJournal(time.Date(2000, 1, 5, 0, 0, 0, 0, time.Local)); err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	model = press(model, alt(','))
+	if model.CurrentNote.Path() != "journal/2000-01-04.md" {
+		t.Errorf("Expected Alt+, to open the day before, not the previous entry, got %s", model.CurrentNote.Path())
+	}
+
+	model = press(model, tea.KeyMsg{Type: tea.KeyCtrlL})
+	model = press(model, tea.KeyMsg{Type: tea.KeyCtrlD})
+	items := model.List.Items()
+	if !model.JournalFilter || len(items) != 6 {
+		t.Fatalf("Expected Ctrl+D to list the 6 daily notes, got %d items", len(items))
+	}
+	if first := items[0].(file.Note).Path(); first != tomorrow {
+		t.Errorf("Expected the newest entry first, got %s", first)
+	}
+	model = press(model, tea.KeyMsg{Type: tea.KeyEsc})
+	if model.JournalFilter {
+		t.Error("Expected Esc to clear the journal filter")
+	}
+}
+
+func TestModel_JournalKeysElsewhere(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	model := InitialModel(testConfig(tmpDir))
+	model.SetStore(store.NewMemStore())
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(Model)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k"), Alt: true})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(","), Alt: true})
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("."), Alt: true})
+	if !model.SearchVisible || model.SearchInput.Value() != ",." || model.ErrMsg != "" {
+		t.Errorf("Expected Alt+, and Alt+. to reach the search input, got %q (%s)", model.SearchInput.Value(), model.ErrMsg)
+	}
+}
+
+func TestModel_JournalTemplate(t *testing.T) {
+	tmpDir := setupTestNotesDir(t)
+	cfg := testConfig(tmpDir)
+	cfg.Journal = config.Journal{Dir: "daily", Template: "templates/day.md"}
+	model := InitialModel(cfg)
+	mem := store.NewMemStore()
+	mem.MakeDir("templates")
+	mem.Write("templates/day.md", []byte("## {{weekday}}\n\n[[{{yesterday}}]]\n"))
+	model.SetStore(mem)
+
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	if err := model.OpenJournal(day); err != nil {
+		t.Fatalf("OpenJournal failed: %v", err)
+	}
+	data, err := mem.Read("daily/2026-03-01.md")
+	if err != nil {
+		t.Fatalf("Expected the note in the configured folder: %v", err)
+	}
+	if string(data) != "## Sunday\n\n[[2026-02-28]]\n" {
+		t.Errorf("Expected the rendered template, got %q", data)
+	}
+	if !model.isJournal() {
+		t.Error("Expected the opened note to be a daily note")
+	}
+}
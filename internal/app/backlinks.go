@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AbhaySingh002/Totion/internal/search"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openBacklinks lists the notes linking to the open note, each with the
+// line the link is on.
+func (m *Model) openBacklinks() {
+	if m.Links == nil {
+		return
+	}
+	// Notes edited outside Totion since the last look are read again.
+	if err := m.Links.Refresh(context.Background(), m.Store); err != nil {
+		m.ErrMsg = fmt.Sprintf("Error reading links: %v", err)
+		return
+	}
+	backlinks := m.Links.Backlinks(m.CurrentNote.Path())
+	items := make([]list.Item, len(backlinks))
+	for i, b := range backlinks {
+		items[i] = searchItem{search.Match{Path: b.From, Line: b.Line, Text: b.Text, Start: b.Start, End: b.End}}
+	}
+	m.BacklinksList.Title = fmt.Sprintf("Backlinks to %s (%d)", m.CurrentNote.Path(), len(items))
+	m.BacklinksList.SetItems(items)
+	m.BacklinksList.Select(0)
+	m.BacklinksVisible = true
+	m.ErrMsg = ""
+}
+
+// updateBacklinks routes keys on the backlinks screen. Opening a backlink
+// goes to its line and can be undone with the link back key.
+func (m Model) updateBacklinks(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch {
+	case key.Matches(msg, m.Keys.Back), key.Matches(msg, m.Keys.Backlinks):
+		m.BacklinksVisible = false
+		m.ErrMsg = ""
+		return m, nil
+	case key.Matches(msg, m.Keys.Open):
+		match, ok := m.BacklinksList.SelectedItem().(searchItem)
+		if !ok {
+			return m, nil
+		}
+		gen := m.TickGen
+		if err := m.visit(match.Path); err != nil {
+			m.ErrMsg = fmt.Sprintf("Error opening file: %v", err)
+			return m, nil
+		}
+		m.gotoLine(match.Line - 1)
+		m.BacklinksVisible = false
+		if m.TickGen == gen {
+			return m, nil
+		}
+		return m, tickCmd(m.TickGen)
+	}
+	m.BacklinksList, cmd = m.BacklinksList.Update(msg)
+	return m, cmd
+}
+
+func (m Model) backlinksView() string {
+	if len(m.BacklinksList.Items()) == 0 {
+		return m.BacklinksList.Title + "\n\nNo notes link here yet."
+	}
+	return m.BacklinksList.View()
+}
@@ -1,5 +1,7 @@
 package app
 
+import "time"
+
 
 const AsciiArt = ` ______   ______     ______   __     ______     __   __    
 /\__  _\ /\  __ \   /\__  _\ /\ \   /\  __ \   /\ "-.\ \   
@@ -8,9 +10,6 @@ const AsciiArt = ` ______   ______     ______   __     ______     __   __
     \/_/   \/_____/     \/_/   \/_/   \/_____/   \/_/ \/_/ 
                                                            `
 
-const GeneralHelp = "Ctrl+N: New Note • Ctrl+L: List all Notes • Esc: Return to home • Ctrl+C: Quit Totion "
-const SaveHelp = "Ctrl+N: New Note • Ctrl+L: List all Notes • Esc: Return to home • Ctrl+S: Save Note • Ctrl+C: Quit Totion"
-const ListHelp = "Ctrl+N: New Note • Esc: Return to home • Ctrl+C: Quit Totion • Delete / Backspace: Delete Note • Enter: Open Note"
 const SystemPrompt = `"You are an intelligent note assistant that helps users thoughtfully continue their notes.
 Continue the note in a natural, meaningful, and concise way — capturing the same tone or emotion.
 Do not repeat the existing text. Do not add any labels like "Completion:" or quotes. Make sure that sentence is complete. Don't end or start with the "..." .
@@ -18,5 +17,16 @@ Don't add the extra things except the Continuation part, becuse the response is
 Note:
 %s
 Continuation:"`
-const GenaiModel = "gemini-2.5-flash-lite"
-const Api_key = "GEMINI-API-KEY"
\ No newline at end of file
+const WatchInterval = 2 * time.Second
+
+// PreviewDebounce is how long typing must pause before the split preview
+// re-renders.
+const PreviewDebounce = 150 * time.Millisecond
+
+// SplitMinWidth is the narrowest terminal that shows the editor and the
+// preview side by side.
+const SplitMinWidth = 100
+
+// GraphDepth is how many links away from the open note the local graph
+// reaches.
+const GraphDepth = 2
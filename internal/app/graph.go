@@ -0,0 +1,41 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openGraph shows the notes around the open note as a tree.
+func (m *Model) openGraph() {
+	if m.Links == nil {
+		return
+	}
+	if err := m.Links.Refresh(context.Background(), m.Store); err != nil {
+		m.ErrMsg = fmt.Sprintf("Error reading links: %v", err)
+		return
+	}
+	tree := m.Links.Graph(false).Tree(m.CurrentNote.Path(), GraphDepth)
+	m.GraphView.SetContent(tree)
+	m.GraphView.GotoTop()
+	m.GraphVisible = true
+	m.ErrMsg = ""
+}
+
+// updateGraph routes keys on the local graph screen, which only scrolls.
+func (m Model) updateGraph(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if key.Matches(msg, m.Keys.Back) || key.Matches(msg, m.Keys.Graph) {
+		m.GraphVisible = false
+		m.ErrMsg = ""
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.GraphView, cmd = m.GraphView.Update(msg)
+	return m, cmd
+}
+
+func (m Model) graphView() string {
+	return fmt.Sprintf("Links around %s, %d deep\n\n%s", m.CurrentNote.Path(), GraphDepth, m.GraphView.View())
+}
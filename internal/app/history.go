@@ -0,0 +1,134 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AbhaySingh002/Totion/internal/history"
+	"github.com/AbhaySingh002/Totion/internal/styles"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// versionItem is a snapshot in the history list, with its diff against
+// the text currently in the editor.
+type versionItem struct {
+	version history.Version
+	content string
+	diff    []history.DiffLine
+}
+
+func (v versionItem) Title() string { return v.version.Time.Local().Format("2006-01-02 15:04:05") }
+
+func (v versionItem) Description() string {
+	added, removed := history.Stat(v.diff)
+	if added == 0 && removed == 0 {
+		return "same as current"
+	}
+	return fmt.Sprintf("+%d −%d vs current", added, removed)
+}
+
+func (v versionItem) FilterValue() string { return v.Title() }
+
+// openHistory loads the snapshots of the open note into the history screen.
+func (m *Model) openHistory() {
+	if m.History == nil {
+		return
+	}
+	versions, err := m.History.Versions(m.CurrentNote.Path())
+	if err != nil {
+		m.ErrMsg = fmt.Sprintf("History error: %v", err)
+		return
+	}
+	current := m.NoteContent.Value()
+	items := make([]list.Item, 0, len(versions))
+	for _, v := range versions {
+		content, err := m.History.Read(v)
+		if err != nil {
+			continue
+		}
+		items = append(items, versionItem{version: v, content: string(content), diff: history.Diff(string(content), current)})
+	}
+	m.HistoryList.SetItems(items)
+	m.HistoryList.Select(0)
+	m.HistoryVisible = true
+	m.ErrMsg = ""
+	m.showSelectedDiff()
+}
+
+func (m *Model) resizeHistory(width, height int) {
+	listWidth := width / 3
+	m.HistoryList.SetSize(listWidth, height)
+	m.HistoryDiff.Width = width - listWidth - 2
+	m.HistoryDiff.Height = height
+	m.showSelectedDiff()
+}
+
+// showSelectedDiff renders the diff of the selected version against the
+// current text; "+" lines exist only in the current text.
+func (m *Model) showSelectedDiff() {
+	item, ok := m.HistoryList.SelectedItem().(versionItem)
+	if !ok {
+		m.HistoryDiff.SetContent("")
+		return
+	}
+	var b strings.Builder
+	for _, line := range item.diff {
+		text := string(line.Op) + " " + line.Text
+		if w := m.HistoryDiff.Width; w > 0 && len([]rune(text)) > w {
+			text = string([]rune(text)[:w])
+		}
+		switch line.Op {
+		case history.Insert:
+			text = styles.DiffInsertStyle.Render(text)
+		case history.Delete:
+			text = styles.DiffDeleteStyle.Render(text)
+		}
+		b.WriteString(text + "\n")
+	}
+	m.HistoryDiff.SetContent(b.String())
+	m.HistoryDiff.GotoTop()
+}
+
+func (m Model) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch {
+	case key.Matches(msg, m.Keys.Back):
+		m.HistoryVisible = false
+		return m, nil
+	case key.Matches(msg, m.Keys.Open):
+		item, ok := m.HistoryList.SelectedItem().(versionItem)
+		if !ok {
+			return m, nil
+		}
+		if m.ReadOnly {
+			m.ErrMsg = "This note is read-only while another Totion instance has it open"
+			return m, nil
+		}
+		// Snapshot the current text first so restoring can be undone.
+		if err := m.WriteNote(); err != nil {
+			return m, nil
+		}
+		m.NoteContent.SetValue(item.content)
+		m.markDirty()
+		m.HistoryVisible = false
+		m.ErrMsg = "Restored version from " + item.Title()
+		return m, nil
+	case msg.String() == "pgup" || msg.String() == "pgdown":
+		m.HistoryDiff, cmd = m.HistoryDiff.Update(msg)
+		return m, cmd
+	}
+	m.HistoryList, cmd = m.HistoryList.Update(msg)
+	m.showSelectedDiff()
+	return m, cmd
+}
+
+func (m Model) historyView() string {
+	if len(m.HistoryList.Items()) == 0 {
+		return m.HistoryList.Title + "\n\nNo saved versions of this note yet."
+	}
+	diff := lipgloss.NewStyle().MarginLeft(2).Render(m.HistoryDiff.View())
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.HistoryList.View(), diff)
+}
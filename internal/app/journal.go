@@ -0,0 +1,135 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/file"
+	"github.com/AbhaySingh002/Totion/internal/journal"
+	"github.com/AbhaySingh002/Totion/internal/store"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// updateJournal handles the daily note keys: today's note from anywhere,
+// the previous and next day from a daily note.
+func (m Model) updateJournal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	gen := m.TickGen
+	var err error
+	switch {
+	case key.Matches(msg, m.Keys.Today):
+		err = m.OpenJournal(time.Now())
+	case key.Matches(msg, m.Keys.PrevDay):
+		err = m.stepJournal(-1)
+	default:
+		err = m.stepJournal(1)
+	}
+	if err != nil {
+		m.ErrMsg = fmt.Sprintf("Journal error: %v", err)
+		return m, nil
+	}
+	if m.TickGen == gen {
+		return m, nil
+	}
+	return m, tickCmd(m.TickGen)
+}
+
+// OpenJournal opens the daily note of day, creating it from the template
+// when it does not exist yet.
+func (m *Model) OpenJournal(day time.Time) error {
+	name, err := m.journalNote(day)
+	if err != nil {
+		return err
+	}
+	if m.CurrentNote != nil {
+		err = m.visit(name)
+	} else {
+		err = m.OpenOrCreateFile(name)
+	}
+	if err != nil {
+		return err
+	}
+	m.ListVisible = false
+	m.TagsVisible = false
+	m.SearchVisible = false
+	m.CreateFileInputVisible = false
+	return nil
+}
+
+// journalNote returns the path of the daily note of day, writing it first
+// when it does not exist.
+func (m *Model) journalNote(day time.Time) (string, error) {
+	dir := m.Config.Journal.Dir
+	name := journal.Path(dir, day)
+	exists, err := store.Exists(m.Store, name)
+	if err != nil || exists {
+		return name, err
+	}
+	content := file.NewNoteContent("", time.Now()) + journal.Render(journal.DefaultTemplate, day)
+	if t := m.Config.Journal.Template; t != "" {
+		template, err := m.Store.Read(t)
+		if err != nil {
+			return "", fmt.Errorf("reading template: %w", err)
+		}
+		content = journal.Render(string(template), day)
+	}
+	if dir != "" {
+		if err := m.Store.MakeDir(dir); err != nil {
+			return "", err
+		}
+	}
+	if err := m.Store.Write(name, []byte(content)); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// stepJournal moves from the open daily note to the one of the day before
+// (-1) or after (1), creating it like today's when it does not exist yet.
+func (m *Model) stepJournal(step int) error {
+	if m.CurrentNote == nil {
+		return errors.New("no daily note is open")
+	}
+	day, ok := journal.Date(m.Config.Journal.Dir, m.CurrentNote.Path())
+	if !ok {
+		return errors.New("the open note is not a daily note")
+	}
+	return m.OpenJournal(day.AddDate(0, 0, step))
+}
+
+// journalItems lists the daily notes, newest first.
+func (m *Model) journalItems() ([]list.Item, error) {
+	dir := m.Config.Journal.Dir
+	items, err := file.NotesFiles(m.Store, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []list.Item{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]list.Item, 0, len(items))
+	for _, item := range items {
+		if note, ok := item.(file.Note); ok {
+			if _, ok := journal.Date(dir, note.Path()); ok {
+				entries = append(entries, note)
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].(file.Note).Path() > entries[j].(file.Note).Path()
+	})
+	return entries, nil
+}
+
+// isJournal reports whether the open note is a daily note.
+func (m *Model) isJournal() bool {
+	if m.CurrentNote == nil {
+		return false
+	}
+	_, ok := journal.Date(m.Config.Journal.Dir, m.CurrentNote.Path())
+	return ok
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/AbhaySingh002/Totion/internal/file"
+	"github.com/AbhaySingh002/Totion/internal/store"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// navigate handles the keys that move between linked notes.
+func (m Model) navigate(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	gen := m.TickGen
+	var err error
+	switch {
+	case key.Matches(msg, m.Keys.FollowLink):
+		err = m.followLink()
+	case key.Matches(msg, m.Keys.LinkBack):
+		err = m.goBack()
+	default:
+		err = m.goForward()
+	}
+	if err != nil {
+		m.ErrMsg = fmt.Sprintf("Link error: %v", err)
+		return m, nil
+	}
+	if m.TickGen == gen {
+		return m, nil
+	}
+	// The tick loop of the previous note stopped with the switch.
+	return m, tickCmd(m.TickGen)
+}
+
+// linkUnderCursor returns the wiki link the editor cursor is on.
+func (m *Model) linkUnderCursor() (file.WikiLink, bool) {
+	lines := strings.Split(m.NoteContent.Value(), "\n")
+	row := m.NoteContent.Line()
+	if row >= len(lines) {
+		return file.WikiLink{}, false
+	}
+	runes := []rune(lines[row])
+	info := m.NoteContent.LineInfo()
+	col := min(info.StartColumn+info.ColumnOffset, len(runes))
+	return file.WikiLinkAt(lines[row], len(string(runes[:col])))
+}
+
+// followLink opens the note the wiki link under the cursor points to,
+// creating it when it does not exist yet. A new note goes into the folder
+// of the open note unless the link names a folder itself.
+func (m *Model) followLink() error {
+	link, ok := m.linkUnderCursor()
+	if !ok {
+		return errors.New("no [[link]] under the cursor")
+	}
+	notes, err := file.AllNotes(m.Store)
+	if err != nil {
+		return err
+	}
+	from := m.CurrentNote.Path()
+	if target, ok := file.NewNoteResolver(notes).Wiki(link.Target, from); ok {
+		return m.visit(target)
+	}
+	dir, title := file.ParentDir(from), link.Target
+	if strings.Contains(title, "/") {
+		ref := strings.TrimPrefix(title, "/")
+		dir, title = file.ParentDir(ref), path.Base(ref)
+	}
+	if _, _, err := file.NotePath(dir, title); err != nil {
+		return err
+	}
+	if err := m.leaveNote(); err != nil {
+		return err
+	}
+	if dir != "" {
+		if err := m.Store.MakeDir(dir); err != nil {
+			return err
+		}
+	}
+	if err := m.CreateNote(dir, title); err != nil {
+		return err
+	}
+	m.LinkBack = append(m.LinkBack, from)
+	m.LinkForward = nil
+	return nil
+}
+
+// visit opens the note at name from the open note, which can be returned
+// to with the link back key.
+func (m *Model) visit(name string) error {
+	from := m.CurrentNote.Path()
+	if name == from {
+		return nil
+	}
+	if err := m.leaveNote(); err != nil {
+		return err
+	}
+	if err := m.OpenOrCreateFile(name); err != nil {
+		return err
+	}
+	m.LinkBack = append(m.LinkBack, from)
+	m.LinkForward = nil
+	return nil
+}
+
+// goBack returns to the note visited before the open one. Notes deleted in
+// the meantime are skipped.
+func (m *Model) goBack() error {
+	return m.step(&m.LinkBack, &m.LinkForward, "back")
+}
+
+// goForward undoes goBack.
+func (m *Model) goForward() error {
+	return m.step(&m.LinkForward, &m.LinkBack, "forward")
+}
+
+// step opens the last note of from and pushes the open note onto to.
+func (m *Model) step(from, to *[]string, direction string) error {
+	for len(*from) > 0 {
+		name := (*from)[len(*from)-1]
+		*from = (*from)[:len(*from)-1]
+		exists, err := store.Exists(m.Store, name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if err := m.leaveNote(); err != nil {
+			*from = append(*from, name)
+			return err
+		}
+		current := m.CurrentNote.Path()
+		if err := m.OpenOrCreateFile(name); err != nil {
+			return err
+		}
+		*to = append(*to, current)
+		return nil
+	}
+	return fmt.Errorf("no note to go %s to", direction)
+}
+
+// leaveNote writes the open note before another one replaces it in the
+// editor.
+func (m *Model) leaveNote() error {
+	if err := m.WriteNote(); err != nil {
+		return err
+	}
+	if m.Index != nil {
+		m.Index.Save(m.Store)
+	}
+	return nil
+}
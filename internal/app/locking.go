@@ -0,0 +1,121 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/lock"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errReadOnlyEdits is returned by WriteNote when the lock on the note was
+// lost while it had unsaved edits, which can then only go to a copy.
+var errReadOnlyEdits = errors.New("another Totion instance took over the note before your edits were saved")
+
+// lockNote takes the lock on the note that was just opened. When another
+// instance holds it the note is opened read-only and the lock prompt asks
+// whether to stay read-only or take the lock over.
+func (m *Model) lockNote() {
+	m.ReadOnly = false
+	m.LockOwner = nil
+	if m.Locker == nil {
+		return
+	}
+	err := m.Locker.Acquire(m.CurrentNote.Path())
+	var locked *lock.LockedError
+	switch {
+	case errors.As(err, &locked):
+		m.ReadOnly = true
+		m.LockOwner = &locked.Owner
+	case err != nil:
+		// Locking is advisory; failing to lock must not keep a note closed.
+		m.ErrMsg = fmt.Sprintf("Lock error: %v", err)
+	default:
+		m.LockedAt = time.Now()
+	}
+}
+
+// unlockNote releases the lock on the open note, if this instance holds it.
+func (m *Model) unlockNote() {
+	if m.Locker == nil || m.CurrentNote == nil || m.ReadOnly {
+		return
+	}
+	m.Locker.Release(m.CurrentNote.Path())
+}
+
+// checkLock renews the lock on the open note so it does not go stale, or
+// when writing, makes sure no other instance has taken it over since. A
+// lost lock turns the note read-only and brings up the lock prompt.
+func (m *Model) checkLock(force bool) error {
+	if m.Locker == nil || m.CurrentNote == nil || m.ReadOnly {
+		return nil
+	}
+	if !force && time.Since(m.LockedAt) < m.Locker.StaleAfter/5 {
+		return nil
+	}
+	err := m.Locker.Refresh(m.CurrentNote.Path())
+	var locked *lock.LockedError
+	if errors.As(err, &locked) {
+		m.ReadOnly = true
+		m.LockOwner = &locked.Owner
+		return err
+	}
+	if err == nil {
+		m.LockedAt = time.Now()
+	}
+	// Other errors only mean the lock could not be renewed; saving can go on.
+	return nil
+}
+
+// updateLockPrompt handles the prompt shown for notes locked by another
+// Totion instance.
+func (m Model) updateLockPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "r":
+		m.LockOwner = nil
+		m.ErrMsg = "Opened read-only"
+	case msg.String() == "c" && m.Dirty:
+		name, err := m.saveCopy()
+		if err != nil {
+			m.ErrMsg = fmt.Sprintf("Save error: %v", err)
+			return m, nil
+		}
+		m.LockOwner = nil
+		m.ErrMsg = "Saved your edits as " + name
+	case msg.String() == "t":
+		if err := m.Locker.TakeOver(m.CurrentNote.Path()); err != nil {
+			m.ErrMsg = fmt.Sprintf("Lock error: %v", err)
+			return m, nil
+		}
+		m.LockOwner = nil
+		m.ReadOnly = false
+		m.LockedAt = time.Now()
+		m.ErrMsg = "Took over the lock"
+	case key.Matches(msg, m.Keys.Back) && m.Dirty:
+		m.ErrMsg = "Your edits are not saved: keep them in a copy or take over the lock"
+	case key.Matches(msg, m.Keys.Back):
+		// Nothing can be saved without the lock, so the note is closed as is.
+		m.LockOwner = nil
+		m.CurrentNote = nil
+		m.ReadOnly = false
+		m.Dirty = false
+		m.NoteContent.SetValue("")
+		m.ErrMsg = ""
+	}
+	return m, nil
+}
+
+func (m Model) lockView() string {
+	if m.Dirty {
+		return fmt.Sprintf("%s was taken over by another Totion instance\n(%s)\nbefore your edits were saved.\n\n"+
+			"  c  save your edits as a copy and keep editing it\n"+
+			"  t  take the lock back and save them here\n"+
+			"  r  keep the note open read-only\n", m.CurrentNote.Path(), m.LockOwner)
+	}
+	return fmt.Sprintf("%s is open in another Totion instance\n(%s).\n\n"+
+		"  r  open it read-only\n"+
+		"  t  take over the lock and edit it here\n"+
+		"  %s  close it\n", m.CurrentNote.Path(), m.LockOwner, m.Keys.Back.Help().Key)
+}
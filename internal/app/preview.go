@@ -0,0 +1,144 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/styles"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Layout selects how an open note is shown.
+type Layout int
+
+const (
+	LayoutEditor Layout = iota
+	LayoutPreview
+	LayoutSplit
+)
+
+// previewMsg re-renders the split preview once typing pauses. gen ties it
+// to the edit that scheduled it so only the last one renders.
+type previewMsg struct {
+	gen int
+}
+
+func previewCmd(gen int) tea.Cmd {
+	return tea.Tick(PreviewDebounce, func(time.Time) tea.Msg {
+		return previewMsg{gen: gen}
+	})
+}
+
+// cycleLayout switches the open note from editor to preview to split and
+// back to the editor.
+func (m *Model) cycleLayout() {
+	m.Layout = (m.Layout + 1) % 3
+	m.resizePanes()
+	if m.Layout == LayoutEditor {
+		return
+	}
+	m.renderPreview()
+	if m.Layout == LayoutPreview {
+		m.PreviewView.GotoTop()
+	} else {
+		m.syncPreview()
+	}
+}
+
+// splitShown reports whether the editor and preview are side by side.
+// Narrow terminals get the editor alone instead.
+func (m *Model) splitShown() bool {
+	return m.Layout == LayoutSplit && m.Width >= SplitMinWidth
+}
+
+// resizePanes sizes the editor and preview for the window and the layout,
+// the same way for every screen as the WindowSizeMsg handler.
+func (m *Model) resizePanes() {
+	h, v := styles.DocStyle.GetFrameSize()
+	width, height := m.Width-h, m.Height-v-10
+	editorWidth, previewWidth := width, width
+	if m.splitShown() {
+		gap := styles.PaneStyle.GetHorizontalFrameSize()
+		editorWidth = (width - gap) / 2
+		previewWidth = width - gap - editorWidth
+	}
+	m.NoteContent.SetWidth(editorWidth)
+	m.NoteContent.SetHeight(height)
+	if m.PreviewView.Width != previewWidth && m.Layout != LayoutEditor {
+		m.PreviewView.Width = previewWidth
+		m.renderPreview()
+	}
+	m.PreviewView.Width = previewWidth
+	m.PreviewView.Height = height
+}
+
+// renderPreview renders the editor content into the preview, keeping the
+// scroll position so it can be called again whenever the text or the width
+// changes.
+func (m *Model) renderPreview() {
+	out, err := m.Markdown.Render(m.NoteContent.Value(), m.PreviewView.Width, styles.MarkdownStyle())
+	if err != nil {
+		m.ErrMsg = fmt.Sprintf("Error rendering preview: %v", err)
+		out = m.NoteContent.Value()
+	}
+	m.PreviewView.SetContent(out)
+}
+
+// syncPreview scrolls the split preview to the same relative position as
+// the editor cursor. Rendered markdown has no line-by-line mapping to the
+// source, so the position is matched proportionally.
+func (m *Model) syncPreview() {
+	lines := m.NoteContent.LineCount()
+	scrollable := m.PreviewView.TotalLineCount() - m.PreviewView.Height
+	if lines <= 1 || scrollable <= 0 {
+		m.PreviewView.GotoTop()
+		return
+	}
+	m.PreviewView.SetYOffset(m.NoteContent.Line() * scrollable / (lines - 1))
+}
+
+// editorChanged runs after every edit in the split layout and schedules a
+// debounced render of the preview.
+func (m *Model) editorChanged() tea.Cmd {
+	m.PreviewGen++
+	return previewCmd(m.PreviewGen)
+}
+
+// updatePreview routes keys while only the preview is shown: the layout
+// key moves on to the split layout, Back returns to the editor and
+// everything else scrolls.
+func (m Model) updatePreview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.Keys.Preview):
+		m.cycleLayout()
+		return m, nil
+	case key.Matches(msg, m.Keys.Back):
+		m.Layout = LayoutEditor
+		m.resizePanes()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.PreviewView, cmd = m.PreviewView.Update(msg)
+	return m, cmd
+}
+
+// scrollSplit moves the editor cursor with the mouse wheel in the split
+// layout, and the preview along with it.
+func (m *Model) scrollSplit(msg tea.MouseMsg) {
+	for i := 0; i < m.PreviewView.MouseWheelDelta; i++ {
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.NoteContent.CursorUp()
+		case tea.MouseButtonWheelDown:
+			m.NoteContent.CursorDown()
+		}
+	}
+	m.syncPreview()
+}
+
+// splitView puts the editor and the preview side by side.
+func (m Model) splitView() string {
+	return lipgloss.JoinHorizontal(lipgloss.Top, m.NoteContent.View(), styles.PaneStyle.Render(m.PreviewView.View()))
+}
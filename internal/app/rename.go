@@ -0,0 +1,121 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/AbhaySingh002/Totion/internal/file"
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+// startRename shows the name prompt prefilled with the current title of
+// note, or with its file name when the title is one the user set, which is
+// kept whatever the note is renamed to.
+func (m *Model) startRename(note file.Note) {
+	m.RenamePath = note.Path()
+	m.RenameTitle = ownTitle(note)
+	m.InputMode = InputRename
+	if m.RenameTitle != "" {
+		m.NewFileInput.SetValue(file.NoteName(note.Path()))
+	} else {
+		m.NewFileInput.SetValue(note.Title())
+	}
+	m.NewFileInput.CursorEnd()
+	m.ListVisible = false
+	m.CreateFileInputVisible = true
+	m.ErrMsg = ""
+}
+
+// renameNote moves the note at oldPath to newPath, taking its history
+// along, and rewrites links to it in every note. title is the front matter
+// title the note keeps, empty when the file name shows it. It returns the
+// number of notes whose links were updated.
+func (m *Model) renameNote(oldPath, newPath, title string) (int, error) {
+	if newPath == oldPath {
+		return 0, m.retitle(oldPath, title)
+	}
+	// On case-insensitive filesystems a change of case finds the note itself.
+	if !strings.EqualFold(newPath, oldPath) {
+		exists, err := store.Exists(m.Store, newPath)
+		if err != nil {
+			return 0, err
+		}
+		if exists {
+			return 0, fmt.Errorf("a note named %s already exists", newPath)
+		}
+	}
+	// Links are resolved against the notes as they were before the rename.
+	notes, err := file.AllNotes(m.Store)
+	if err != nil {
+		return 0, err
+	}
+	resolver := file.NewNoteResolver(notes)
+	if err := m.Store.Rename(oldPath, newPath); err != nil {
+		return 0, err
+	}
+	if err := m.retitle(newPath, title); err != nil {
+		return 0, err
+	}
+	if m.History != nil {
+		if err := m.History.Move(oldPath, newPath); err != nil {
+			m.ErrMsg = fmt.Sprintf("History error: %v", err)
+		}
+	}
+	if m.Index != nil {
+		// Rewritten notes are picked up by the next refresh.
+		m.Index.Remove(oldPath)
+		defer m.Index.Save(m.Store)
+	}
+	if m.Links != nil {
+		m.Links.Remove(oldPath)
+	}
+	updated := 0
+	err = file.WalkNotes(m.Store, func(name string, info store.Info) error {
+		content, err := m.Store.Read(name)
+		if err != nil {
+			return err
+		}
+		rewritten, n := file.RenameLinks(string(content), name, oldPath, newPath, resolver)
+		if n == 0 {
+			return nil
+		}
+		if err := m.Store.Write(name, []byte(rewritten)); err != nil {
+			return err
+		}
+		updated++
+		return nil
+	})
+	if err != nil {
+		return updated, fmt.Errorf("renamed, but updating links failed: %w", err)
+	}
+	return updated, nil
+}
+
+// ownTitle returns the front matter title of note when the user set it,
+// and an empty string when there is none or it only holds a name the file
+// name could not show, such as "Q1: goals" for "Q1- goals.md".
+func ownTitle(note file.Note) string {
+	title := note.Meta().Title
+	if title == "" {
+		return ""
+	}
+	if p, _, err := file.NotePath(file.ParentDir(note.Path()), title); err == nil && p == note.Path() {
+		return ""
+	}
+	return title
+}
+
+// retitle sets the front matter title of the note at name, dropping it
+// when title is empty.
+func (m *Model) retitle(name, title string) error {
+	content, err := m.Store.Read(name)
+	if err != nil {
+		return err
+	}
+	retitled := file.SetTitle(content, title)
+	if bytes.Equal(retitled, content) {
+		return nil
+	}
+	return m.Store.Write(name, retitled)
+}
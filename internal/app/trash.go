@@ -0,0 +1,121 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/AbhaySingh002/Totion/internal/trash"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// trashItem is a deleted note in the trash list.
+type trashItem struct {
+	entry trash.Entry
+}
+
+func (t trashItem) Title() string { return t.entry.Path }
+
+func (t trashItem) Description() string {
+	return "deleted " + t.entry.Deleted.Local().Format("2006-01-02 15:04")
+}
+
+func (t trashItem) FilterValue() string { return t.entry.Path }
+
+// trashNote moves a note from the list into the trash.
+func (m *Model) trashNote(name string) {
+	if _, err := m.Trash.Move(name); err != nil {
+		m.ErrMsg = fmt.Sprintf("Error moving note to trash: %v", err)
+		return
+	}
+	if m.Index != nil {
+		m.Index.Remove(name)
+		m.Index.Save(m.Store)
+	}
+	if m.Links != nil {
+		m.Links.Remove(name)
+	}
+	m.ErrMsg = fmt.Sprintf("Moved %s to trash • %s: Open trash", name, m.Keys.Trash.Help().Key)
+	m.refreshList()
+}
+
+// openTrash purges expired notes and shows the rest in the trash screen.
+func (m *Model) openTrash() {
+	m.ErrMsg = ""
+	if _, err := m.Trash.PurgeExpired(); err != nil {
+		m.ErrMsg = fmt.Sprintf("Error purging trash: %v", err)
+	}
+	m.refreshTrash()
+	m.TrashList.Select(0)
+	m.ListVisible = false
+	m.TrashVisible = true
+	m.PurgePending = false
+}
+
+func (m *Model) refreshTrash() {
+	entries, err := m.Trash.List()
+	if err != nil {
+		m.ErrMsg = fmt.Sprintf("Error reading trash: %v", err)
+		return
+	}
+	items := make([]list.Item, len(entries))
+	for i, e := range entries {
+		items[i] = trashItem{entry: e}
+	}
+	m.TrashList.SetItems(items)
+}
+
+// updateTrash routes keys on the trash screen. Purging asks for a second
+// press of the same key since it cannot be undone.
+func (m Model) updateTrash(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	purgePending := m.PurgePending
+	m.PurgePending = false
+	switch {
+	case key.Matches(msg, m.Keys.Back):
+		m.TrashVisible = false
+		m.ListVisible = true
+		m.ErrMsg = ""
+		m.refreshList()
+		return m, nil
+	case key.Matches(msg, m.Keys.Open):
+		item, ok := m.TrashList.SelectedItem().(trashItem)
+		if !ok {
+			return m, nil
+		}
+		restored, err := m.Trash.Restore(item.entry)
+		if err != nil {
+			m.ErrMsg = fmt.Sprintf("Error restoring note: %v", err)
+			return m, nil
+		}
+		m.ErrMsg = "Restored " + restored
+		m.refreshTrash()
+		return m, nil
+	case key.Matches(msg, m.Keys.Delete):
+		item, ok := m.TrashList.SelectedItem().(trashItem)
+		if !ok {
+			return m, nil
+		}
+		if !purgePending {
+			m.PurgePending = true
+			m.ErrMsg = fmt.Sprintf("Press %s again to permanently delete %s", msg.String(), item.entry.Path)
+			return m, nil
+		}
+		if err := m.Trash.Purge(item.entry); err != nil {
+			m.ErrMsg = fmt.Sprintf("Error deleting note: %v", err)
+			return m, nil
+		}
+		m.ErrMsg = "Permanently deleted " + item.entry.Path
+		m.refreshTrash()
+		return m, nil
+	}
+	m.TrashList, cmd = m.TrashList.Update(msg)
+	return m, cmd
+}
+
+func (m Model) trashView() string {
+	if len(m.TrashList.Items()) == 0 {
+		return m.TrashList.Title + "\n\nTrash is empty."
+	}
+	return m.TrashList.View()
+}
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/file"
+	"github.com/AbhaySingh002/Totion/internal/store"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errConflict is returned by WriteNote when the note was changed by another
+// program since it was loaded or last saved.
+var errConflict = errors.New("note changed on disk")
+
+// watchMsg polls the store for changes made outside of Totion. Polling
+// through the NoteStore works the same for every backend and platform.
+type watchMsg time.Time
+
+func watchCmd() tea.Cmd {
+	return tea.Tick(WatchInterval, func(t time.Time) tea.Msg {
+		return watchMsg(t)
+	})
+}
+
+// loaded remembers what the open note looks like on disk so later changes
+// by other programs can be told apart from our own writes.
+func (m *Model) loaded(info store.Info, content []byte) {
+	m.DiskInfo = info
+	m.DiskContent = string(content)
+}
+
+// changedOnDisk reports whether the open note differs from the version it
+// was loaded from or last saved as. The content is only read again when
+// its size or modification time moved.
+func (m *Model) changedOnDisk() (bool, error) {
+	info, err := m.Store.Stat(m.CurrentNote.Path())
+	if errors.Is(err, fs.ErrNotExist) {
+		// A deleted note is simply written again.
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if info.Size == m.DiskInfo.Size && info.ModTime.Equal(m.DiskInfo.ModTime) {
+		return false, nil
+	}
+	content, err := m.Store.Read(m.CurrentNote.Path())
+	if err != nil {
+		return false, err
+	}
+	if string(content) == m.DiskContent {
+		m.DiskInfo = info
+		return false, nil
+	}
+	return true, nil
+}
+
+// checkDisk runs on every watchMsg. An open note without local edits is
+// reloaded when it changes on disk, with local edits the conflict prompt is
+// shown. A visible list is refreshed when its folder changes.
+func (m *Model) checkDisk() {
+	if m.CurrentNote != nil {
+		m.checkLock(false)
+	}
+	if m.CurrentNote != nil && !m.Conflict {
+		changed, err := m.changedOnDisk()
+		switch {
+		case err != nil:
+			m.ErrMsg = fmt.Sprintf("Error checking note on disk: %v", err)
+		case changed && m.Dirty:
+			m.Conflict = true
+		case changed:
+			if err := m.reloadNote(); err != nil {
+				m.ErrMsg = fmt.Sprintf("Error reloading note: %v", err)
+			} else {
+				m.ErrMsg = "Note changed on disk and was reloaded"
+			}
+		}
+	}
+	if m.ListVisible && m.TagFilter == "" && !m.JournalFilter {
+		if stamp := m.listStamp(); stamp != m.ListStamp {
+			index := m.List.Index()
+			m.refreshList()
+			m.List.Select(index)
+		}
+	}
+}
+
+// listStamp summarises the entries of CurrentDir so the list is only
+// reloaded when something in it changed.
+func (m *Model) listStamp() string {
+	entries, err := m.Store.List(m.CurrentDir)
+	if err != nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name, ".") {
+			continue
+		}
+		fmt.Fprintf(&b, "%s %d %d\n", e.Name, e.Size, e.ModTime.UnixNano())
+	}
+	return b.String()
+}
+
+// reloadNote replaces the editor content with the note on disk, keeping
+// the cursor on the same line where possible.
+func (m *Model) reloadNote() error {
+	line := m.NoteContent.Line()
+	if err := m.OpenOrCreateFile(m.CurrentNote.Path()); err != nil {
+		return err
+	}
+	m.gotoLine(line)
+	return nil
+}
+
+// saveCopy writes the editor content next to the open note as a conflict
+// copy and continues editing the copy, leaving the note on disk alone.
+func (m *Model) saveCopy() (string, error) {
+	base := strings.TrimSuffix(m.CurrentNote.Path(), ".md")
+	name := base + " (conflict copy).md"
+	for n := 2; ; n++ {
+		exists, err := store.Exists(m.Store, name)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			break
+		}
+		name = fmt.Sprintf("%s (conflict copy %d).md", base, n)
+	}
+	content := m.NoteContent.Value()
+	note := file.ParseNote(name, time.Now(), []byte(content))
+	m.unlockNote()
+	m.CurrentNote = &note
+	m.lockNote()
+	if err := m.WriteNote(); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// updateConflict handles the prompt shown when the open note changed on
+// disk while it has unsaved edits. It stays up until one of the choices is
+// made, since any other way out would lose one of the two versions.
+func (m Model) updateConflict(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "r":
+		m.Conflict = false
+		if err := m.reloadNote(); err != nil {
+			m.ErrMsg = fmt.Sprintf("Error reloading note: %v", err)
+			return m, nil
+		}
+		m.ErrMsg = "Reloaded the version on disk"
+		return m, tickCmd(m.TickGen)
+	case "o":
+		m.Conflict = false
+		// Take the version on disk as the base so the write goes through.
+		if info, err := m.Store.Stat(m.CurrentNote.Path()); err == nil {
+			content, _ := m.Store.Read(m.CurrentNote.Path())
+			m.loaded(info, content)
+		}
+		if err := m.WriteNote(); err == nil {
+			m.ErrMsg = "Overwrote the version on disk"
+		}
+		return m, nil
+	case "c":
+		m.Conflict = false
+		name, err := m.saveCopy()
+		if err != nil {
+			m.ErrMsg = fmt.Sprintf("Error saving copy: %v", err)
+			return m, nil
+		}
+		m.ErrMsg = "Saved your version as " + name
+		return m, nil
+	}
+	return m, nil
+}
+
+func (m Model) conflictView() string {
+	return fmt.Sprintf("%s was changed outside Totion while you were editing it.\n\n"+
+		"  r  reload the version on disk and drop your edits\n"+
+		"  o  overwrite it with your version\n"+
+		"  c  save your version as a copy\n", m.CurrentNote.Path())
+}
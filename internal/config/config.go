@@ -0,0 +1,240 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/file"
+	"github.com/AbhaySingh002/Totion/internal/keymap"
+	"github.com/AbhaySingh002/Totion/internal/styles"
+	"github.com/BurntSushi/toml"
+)
+
+// EnvDir names the environment variable overriding the notes directory.
+const EnvDir = "TOTION_DIR"
+
+// Config holds the user's settings from the config file. Settings missing
+// from the file keep their values from Default.
+type Config struct {
+	// NotesDir is the folder holding the notes. "~" expands to the home
+	// directory; empty means the default of ~/.totion.
+	NotesDir     string       `toml:"notes_dir"`
+	Editor       Editor       `toml:"editor"`
+	Autocomplete Autocomplete `toml:"autocomplete"`
+	Trash        Trash        `toml:"trash"`
+	Journal      Journal      `toml:"journal"`
+	// Theme is "auto", a built-in theme or one of Themes.
+	Theme  string                  `toml:"theme"`
+	Themes map[string]styles.Theme `toml:"themes,omitempty"`
+	// Keys maps action names to the keys bound to them, see keymap.Defaults.
+	// Actions missing from the file keep their default keys.
+	Keys map[string][]string `toml:"keys"`
+}
+
+type Editor struct {
+	// NameLimit is the maximum length of names typed for notes and folders.
+	NameLimit int `toml:"name_limit"`
+	// AutoSaveIdle saves after typing pauses this long, AutoSaveInterval
+	// at the latest this long after the last save. Zero turns either off.
+	AutoSaveIdle     time.Duration `toml:"autosave_idle"`
+	AutoSaveInterval time.Duration `toml:"autosave_interval"`
+}
+
+type Autocomplete struct {
+	// Enabled turns autocomplete on when a note is opened.
+	Enabled bool `toml:"enabled"`
+	// Delay is the number of seconds without typing before a suggestion
+	// is requested.
+	Delay       int     `toml:"delay"`
+	Temperature float64 `toml:"temperature"`
+	Model       string  `toml:"model"`
+	// APIKey is the Gemini API key. GEMINI_API_KEY takes precedence, and
+	// `totion auth set` keeps it out of the config file altogether.
+	APIKey string `toml:"api_key,omitempty"`
+}
+
+type Trash struct {
+	// RetentionDays is how long deleted notes stay in the trash; zero keeps
+	// them until purged by hand.
+	RetentionDays int `toml:"retention_days"`
+}
+
+type Journal struct {
+	// Dir is the folder of the daily notes inside the notes directory.
+	Dir string `toml:"dir"`
+	// Template is a note inside the notes directory that new daily notes
+	// are made from, see journal.Render. Empty uses journal.DefaultTemplate.
+	Template string `toml:"template,omitempty"`
+}
+
+// Default returns the settings used when there is no config file.
+func Default() Config {
+	return Config{
+		Editor: Editor{
+			NameLimit:        30,
+			AutoSaveIdle:     2 * time.Second,
+			AutoSaveInterval: 30 * time.Second,
+		},
+		Autocomplete: Autocomplete{
+			Enabled:     false,
+			Delay:       3,
+			Temperature: 0.9,
+			Model:       "gemini-2.5-flash-lite",
+		},
+		Trash:   Trash{RetentionDays: 30},
+		Journal: Journal{Dir: "journal"},
+		Theme:   styles.Auto,
+		Keys:    keymap.Defaults(),
+	}
+}
+
+// Validate reports the first setting that is out of range.
+func (c Config) Validate() error {
+	switch {
+	case c.Editor.NameLimit < 1 || c.Editor.NameLimit > 200:
+		return fmt.Errorf("editor.name_limit must be between 1 and 200, got %d", c.Editor.NameLimit)
+	case c.Editor.AutoSaveIdle < 0:
+		return fmt.Errorf("editor.autosave_idle cannot be negative, got %s", c.Editor.AutoSaveIdle)
+	case c.Editor.AutoSaveInterval < 0:
+		return fmt.Errorf("editor.autosave_interval cannot be negative, got %s", c.Editor.AutoSaveInterval)
+	case c.Autocomplete.Delay < 1:
+		return fmt.Errorf("autocomplete.delay must be at least 1 second, got %d", c.Autocomplete.Delay)
+	case c.Autocomplete.Temperature < 0 || c.Autocomplete.Temperature > 2:
+		return fmt.Errorf("autocomplete.temperature must be between 0 and 2, got %g", c.Autocomplete.Temperature)
+	case strings.TrimSpace(c.Autocomplete.Model) == "":
+		return errors.New("autocomplete.model cannot be empty")
+	case c.Trash.RetentionDays < 0:
+		return fmt.Errorf("trash.retention_days cannot be negative, got %d", c.Trash.RetentionDays)
+	}
+	if c.Journal.Dir != "" {
+		if err := file.ValidPath(c.Journal.Dir); err != nil {
+			return fmt.Errorf("journal.dir: %w", err)
+		}
+	}
+	if c.Journal.Template != "" {
+		if err := file.ValidPath(c.Journal.Template); err != nil || !strings.HasSuffix(c.Journal.Template, ".md") {
+			return fmt.Errorf("journal.template must be the path of a note inside the notes directory, got %q", c.Journal.Template)
+		}
+	}
+	if _, err := keymap.New(c.Keys); err != nil {
+		return fmt.Errorf("keys: %w", err)
+	}
+	for _, name := range sortedKeys(c.Themes) {
+		if _, ok := styles.Themes[name]; ok || name == styles.Auto {
+			return fmt.Errorf("themes.%s: the name of a built-in theme cannot be reused", name)
+		}
+		if err := c.Themes[name].Validate(); err != nil {
+			return fmt.Errorf("themes.%s: %w", name, err)
+		}
+	}
+	if _, ok := c.Themes[c.Theme]; !ok && c.Theme != styles.Auto {
+		if _, ok := styles.Themes[c.Theme]; !ok {
+			return fmt.Errorf("theme must be %q, one of %v or a theme from [themes], got %q", styles.Auto, styles.ThemeNames(), c.Theme)
+		}
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// TrashRetention returns the trash retention as a duration.
+func (c Config) TrashRetention() time.Duration {
+	return time.Duration(c.Trash.RetentionDays) * 24 * time.Hour
+}
+
+// Write prints c as TOML, the format of the config file.
+func (c Config) Write(w io.Writer) error {
+	enc := toml.NewEncoder(w)
+	enc.Indent = ""
+	return enc.Encode(c)
+}
+
+// Path returns the location of the config file,
+// $XDG_CONFIG_HOME/totion/config.toml or the platform's equivalent.
+func Path() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		var err error
+		if dir, err = os.UserConfigDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, "totion", "config.toml"), nil
+}
+
+// Load reads the config file at path on top of the defaults and validates
+// the result. A missing file is not an error and yields Default().
+func Load(path string) (Config, error) {
+	cfg := Default()
+	meta, err := toml.DecodeFile(path, &cfg)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Default(), nil
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return Config{}, fmt.Errorf("reading %s: unknown setting %q", path, undecoded[0].String())
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// DefaultNotesDir returns ~/.totion.
+func DefaultNotesDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".totion"), nil
+}
+
+// ResolveNotesDir picks the notes directory from, in order of precedence,
+// the --dir flag, the TOTION_DIR environment variable, the config file and
+// the default. It also returns which of them it used.
+func ResolveNotesDir(flagDir, envDir string, cfg Config) (dir, source string, err error) {
+	switch {
+	case flagDir != "":
+		dir, source = flagDir, "--dir flag"
+	case envDir != "":
+		dir, source = envDir, EnvDir
+	case cfg.NotesDir != "":
+		dir, source = cfg.NotesDir, "config file"
+	default:
+		dir, err = DefaultNotesDir()
+		return dir, "default", err
+	}
+	if dir, err = expandHome(dir); err != nil {
+		return "", "", err
+	}
+	dir, err = filepath.Abs(dir)
+	return dir, source, err
+}
+
+// expandHome replaces a leading "~" with the home directory.
+func expandHome(p string) (string, error) {
+	if p != "~" && !strings.HasPrefix(p, "~/") && !strings.HasPrefix(p, `~\`) {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, p[1:]), nil
+}
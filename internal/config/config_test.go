@@ -0,0 +1,271 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/styles"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+	path, err := Path()
+	if err != nil {
+		t.Fatalf("Path failed: %v", err)
+	}
+	if path != filepath.Join("/tmp/xdg", "totion", "config.toml") {
+		t.Errorf("Unexpected config path %s", path)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		cfg, err := Load(filepath.Join(t.TempDir(), "missing.toml"))
+		if err != nil {
+			t.Fatalf("Expected no error for a missing file, got %v", err)
+		}
+		if !reflect.DeepEqual(cfg, Default()) {
+			t.Errorf("Expected default config, got %+v", cfg)
+		}
+	})
+
+	t.Run("notes dir", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, `notes_dir = "~/vault"`))
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.NotesDir != "~/vault" {
+			t.Errorf("Expected notes_dir '~/vault', got '%s'", cfg.NotesDir)
+		}
+	})
+
+	t.Run("unknown setting", func(t *testing.T) {
+		_, err := Load(writeConfig(t, `notes_directory = "x"`))
+		if err == nil || !strings.Contains(err.Error(), "notes_directory") {
+			t.Errorf("Expected an unknown setting error, got %v", err)
+		}
+	})
+
+	t.Run("syntax error", func(t *testing.T) {
+		if _, err := Load(writeConfig(t, `notes_dir = `)); err == nil {
+			t.Error("Expected a syntax error")
+		}
+	})
+}
+
+func TestResolveNotesDir(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	cfg := Config{NotesDir: "~/from-config"}
+
+	tests := []struct {
+		name, flag, env string
+		cfg             Config
+		dir, source     string
+	}{
+		{"flag wins", "/flag", "/env", cfg, "/flag", "--dir flag"},
+		{"env before config", "", "/env", cfg, "/env", EnvDir},
+		{"config", "", "", cfg, filepath.Join(home, "from-config"), "config file"},
+		{"default", "", "", Config{}, filepath.Join(home, ".totion"), "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, source, err := ResolveNotesDir(tt.flag, tt.env, tt.cfg)
+			if err != nil {
+				t.Fatalf("ResolveNotesDir failed: %v", err)
+			}
+			if dir != tt.dir || source != tt.source {
+				t.Errorf("Expected %s from %s, got %s from %s", tt.dir, tt.source, dir, source)
+			}
+		})
+	}
+
+	t.Run("relative paths become absolute", func(t *testing.T) {
+		dir, _, _ := ResolveNotesDir("notes", "", Config{})
+		if !filepath.IsAbs(dir) {
+			t.Errorf("Expected an absolute path, got %s", dir)
+		}
+	})
+}
+
+func TestLoad_Settings(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+theme = "mine"
+
+[editor]
+name_limit = 60
+autosave_idle = "5s"
+
+[autocomplete]
+enabled = true
+temperature = 0.4
+
+[themes.mine]
+base = "light"
+accent = "212"
+
+[keys]
+new_note = ["alt+n"]
+`))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	want := Default()
+	want.Editor.NameLimit = 60
+	want.Editor.AutoSaveIdle = 5 * time.Second
+	want.Autocomplete.Enabled = true
+	want.Autocomplete.Temperature = 0.4
+	want.Theme = "mine"
+	want.Themes = map[string]styles.Theme{"mine": {Base: "light", Accent: "212"}}
+	want.Keys["new_note"] = []string{"alt+n"}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Expected settings on top of the defaults\ngot  %+v\nwant %+v", cfg, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("Expected defaults to be valid, got %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		key    string
+	}{
+		{"name limit", func(c *Config) { c.Editor.NameLimit = 0 }, "editor.name_limit"},
+		{"autosave", func(c *Config) { c.Editor.AutoSaveIdle = -time.Second }, "editor.autosave_idle"},
+		{"delay", func(c *Config) { c.Autocomplete.Delay = 0 }, "autocomplete.delay"},
+		{"temperature", func(c *Config) { c.Autocomplete.Temperature = 2.5 }, "autocomplete.temperature"},
+		{"model", func(c *Config) { c.Autocomplete.Model = " " }, "autocomplete.model"},
+		{"retention", func(c *Config) { c.Trash.RetentionDays = -1 }, "trash.retention_days"},
+		{"journal dir", func(c *Config) { c.Journal.Dir = "../diary" }, "journal.dir"},
+		{"journal template", func(c *Config) { c.Journal.Template = "templates/daily.txt" }, "journal.template"},
+		{"theme", func(c *Config) { c.Theme = "neon" }, "theme must be"},
+		{"colour", func(c *Config) { c.Themes = map[string]styles.Theme{"mine": {Muted: "grey"}} }, "themes.mine: muted"},
+		{"ansi colour", func(c *Config) { c.Themes = map[string]styles.Theme{"mine": {Accent: "256"}} }, "themes.mine: accent"},
+		{"theme base", func(c *Config) { c.Themes = map[string]styles.Theme{"mine": {Base: "mine"}} }, "themes.mine: base"},
+		{"built-in name", func(c *Config) { c.Themes = map[string]styles.Theme{"dark": {}} }, "themes.dark"},
+		{"key conflict", func(c *Config) { c.Keys["rename"] = []string{"ctrl+n"} }, "keys:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("Expected an error about %s, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	cfg := Default()
+	cfg.NotesDir = "/notes"
+	cfg.Editor.AutoSaveInterval = time.Minute
+
+	var b strings.Builder
+	if err := cfg.Write(&b); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	loaded, err := Load(writeConfig(t, b.String()))
+	if err != nil {
+		t.Fatalf("Expected written config to load, got %v", err)
+	}
+	if !reflect.DeepEqual(loaded, cfg) {
+		t.Errorf("Expected round trip to keep settings\ngot  %+v\nwant %+v", loaded, cfg)
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "totion", "credentials")
+
+	if key, err := ReadCredentials(path); err != nil || key != "" {
+		t.Fatalf("Expected no key for a missing file, got %q, %v", key, err)
+	}
+	if err := WriteCredentials(path, "  secret-key\n"); err != nil {
+		t.Fatalf("WriteCredentials failed: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected credentials file: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("Expected mode 0600, got %o", info.Mode().Perm())
+	}
+	if key, err := ReadCredentials(path); err != nil || key != "secret-key" {
+		t.Errorf("Expected secret-key, got %q, %v", key, err)
+	}
+
+	if runtime.GOOS != "windows" {
+		os.Chmod(path, 0o644)
+		if _, err := ReadCredentials(path); err == nil {
+			t.Error("Expected a file readable by others to be rejected")
+		}
+	}
+
+	if err := ClearCredentials(path); err != nil {
+		t.Fatalf("ClearCredentials failed: %v", err)
+	}
+	if err := ClearCredentials(path); err != nil {
+		t.Errorf("Expected clearing twice to succeed, got %v", err)
+	}
+}
+
+func TestResolveAPIKey(t *testing.T) {
+	credentials := filepath.Join(t.TempDir(), "credentials")
+	if err := WriteCredentials(credentials, "from-file"); err != nil {
+		t.Fatal(err)
+	}
+	withKey := Default()
+	withKey.Autocomplete.APIKey = "from-config"
+
+	tests := []struct {
+		name, env   string
+		cfg         Config
+		credentials string
+		key, source string
+	}{
+		{"environment", "from-env", withKey, credentials, "from-env", EnvAPIKey},
+		{"config file", "", withKey, credentials, "from-config", "config file"},
+		{"credentials file", "", Default(), credentials, "from-file", "credentials file"},
+		{"none", "", Default(), filepath.Join(t.TempDir(), "missing"), "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, source, err := ResolveAPIKey(tt.env, tt.cfg, tt.credentials)
+			if err != nil {
+				t.Fatalf("ResolveAPIKey failed: %v", err)
+			}
+			if key != tt.key || source != tt.source {
+				t.Errorf("Expected %q from %q, got %q from %q", tt.key, tt.source, key, source)
+			}
+		})
+	}
+}
+
+func TestMaskKey(t *testing.T) {
+	if got := MaskKey("AIzaSyExample1234"); got != "****1234" {
+		t.Errorf("Expected ****1234, got %q", got)
+	}
+	if got := MaskKey("abc"); got != "***" {
+		t.Errorf("Expected ***, got %q", got)
+	}
+}
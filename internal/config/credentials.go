@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// EnvAPIKey names the environment variable holding the Gemini API key.
+const EnvAPIKey = "GEMINI_API_KEY"
+
+// CredentialsPath returns the location of the file written by `totion auth`,
+// next to the config file.
+func CredentialsPath() (string, error) {
+	path, err := Path()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "credentials"), nil
+}
+
+// ReadCredentials returns the API key stored at path, or "" when there is no
+// such file. Like SSH keys, the file must not be readable by other users.
+func ReadCredentials(path string) (string, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o077 != 0 {
+		return "", fmt.Errorf("%s is accessible by other users; run chmod 600 %s", path, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// WriteCredentials stores key at path, readable only by the current user.
+func WriteCredentials(path, key string) error {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return errors.New("API key cannot be empty")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(key+"\n"), 0o600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing file.
+	return os.Chmod(path, 0o600)
+}
+
+// ClearCredentials removes the credentials file. A missing file is not an
+// error.
+func ClearCredentials(path string) error {
+	err := os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// ResolveAPIKey picks the Gemini API key from, in order of precedence, the
+// GEMINI_API_KEY environment variable, the config file and the credentials
+// file. It also returns which of them it used; both are empty when no key
+// is set anywhere.
+func ResolveAPIKey(envKey string, cfg Config, credentialsPath string) (key, source string, err error) {
+	if key = strings.TrimSpace(envKey); key != "" {
+		return key, EnvAPIKey, nil
+	}
+	if key = strings.TrimSpace(cfg.Autocomplete.APIKey); key != "" {
+		return key, "config file", nil
+	}
+	key, err = ReadCredentials(credentialsPath)
+	if err != nil || key == "" {
+		return "", "", err
+	}
+	return key, "credentials file", nil
+}
+
+// MaskKey shows only the last four characters of key.
+func MaskKey(key string) string {
+	if len(key) <= 4 {
+		return strings.Repeat("*", len(key))
+	}
+	return strings.Repeat("*", 4) + key[len(key)-4:]
+}
@@ -1,43 +1,218 @@
 package file
 
 import (
-	"log"
-	"os"
+	"path"
+	"strings"
+	"time"
 
+	"github.com/AbhaySingh002/Totion/internal/store"
 	"github.com/charmbracelet/bubbles/list"
 )
 
+// Note is a markdown file in the store, identified by its relative path.
 type Note struct {
-	title string
-	desc  string
+	path string
+	desc string
+	meta FrontMatter
+	tags []string
+}
+
+// Title returns the front matter title, or the file name without ".md".
+func (n Note) Title() string {
+	if n.meta.Title != "" {
+		return n.meta.Title
+	}
+	return strings.TrimSuffix(path.Base(n.path), ".md")
 }
 
-func (n Note) Title() string       { return n.title }
 func (n Note) Description() string { return n.desc }
-func (n Note) FilterValue() string { return n.title }
 
-func NotesFiles(notesDir string) []list.Item {
-	entries, err := os.ReadDir(notesDir)
+func (n Note) FilterValue() string {
+	return strings.Join(append([]string{n.Title()}, n.meta.Aliases...), " ")
+}
+
+// Meta returns the parsed front matter of the note.
+func (n Note) Meta() FrontMatter { return n.meta }
+
+// Tags returns the normalized tags from the front matter and the body.
+func (n Note) Tags() []string { return n.tags }
+
+// Path returns the name of the note inside its store.
+func (n Note) Path() string { return n.path }
+
+// Dir returns the folder containing the note, "" for the root.
+func (n Note) Dir() string { return ParentDir(n.path) }
+
+// NewNote builds a Note for the markdown file stored under path. The
+// description shows the updated time from the front matter, falling back
+// to modTime, followed by tags and the creation date when present.
+func NewNote(path string, modTime time.Time, meta FrontMatter) Note {
+	return newNote(path, modTime, meta, MergeTags(meta.Tags))
+}
+
+// ParseNote builds a Note from the raw content of a file, picking up front
+// matter and inline #tags. A malformed front matter block is ignored.
+func ParseNote(path string, modTime time.Time, content []byte) Note {
+	meta, body, err := SplitFrontMatter(content)
+	if err != nil {
+		meta = FrontMatter{}
+	}
+	return newNote(path, modTime, meta, MergeTags(meta.Tags, InlineTags(string(body))))
+}
+
+func newNote(path string, modTime time.Time, meta FrontMatter, tags []string) Note {
+	updated := modTime
+	if !meta.Updated.IsZero() {
+		updated = meta.Updated
+	}
+	parts := []string{updated.Format("2006-01-02 15:04")}
+	if len(tags) > 0 {
+		parts = append(parts, "#"+strings.Join(tags, " #"))
+	}
+	if !meta.Created.IsZero() {
+		parts = append(parts, "created "+meta.Created.Format("2006-01-02"))
+	}
+	return Note{
+		path: path,
+		desc: strings.Join(parts, " • "),
+		meta: meta,
+		tags: tags,
+	}
+}
+
+// Folder is a directory in the store. The parent entry of a listing is a
+// Folder with Parent set, rendered as "..".
+type Folder struct {
+	path   string
+	Parent bool
+}
+
+func (f Folder) Title() string {
+	if f.Parent {
+		return ".."
+	}
+	return path.Base(f.path) + "/"
+}
+
+func (f Folder) Description() string {
+	if f.Parent {
+		return "Parent folder"
+	}
+	return "Folder"
+}
+
+func (f Folder) FilterValue() string { return f.Title() }
+
+// Path returns the folder's location inside its store, "" for the root.
+func (f Folder) Path() string { return f.path }
+
+// ParentDir returns the folder containing p, "" for the root.
+func ParentDir(p string) string {
+	dir := path.Dir(p)
+	if dir == "." || dir == "/" {
+		return ""
+	}
+	return dir
+}
+
+// JoinPath joins a folder and a name into a store path.
+func JoinPath(dir, name string) string {
+	if dir == "" {
+		return name
+	}
+	return path.Join(dir, name)
+}
+
+// Breadcrumbs renders dir as a title such as "All Notes 📒 › work › 2024".
+func Breadcrumbs(dir string) string {
+	crumbs := "All Notes 📒"
+	if dir == "" {
+		return crumbs
+	}
+	for _, part := range strings.Split(dir, "/") {
+		crumbs += " › " + part
+	}
+	return crumbs
+}
+
+// NotesFiles lists the folders and markdown notes directly inside dir,
+// folders first. Outside the root the list starts with a ".." entry.
+func NotesFiles(s store.NoteStore, dir string) ([]list.Item, error) {
+	entries, err := s.List(dir)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	items := make([]list.Item, 0)
+	if dir != "" {
+		items = append(items, Folder{path: ParentDir(dir), Parent: true})
+	}
+	var notes []list.Item
 	for _, e := range entries {
-		if e.IsDir() {
+		name := e.Name
+		if strings.HasPrefix(name, ".") {
 			continue
 		}
-		name := e.Name()
-		if len(name) > 3 && name[len(name)-3:] == ".md" {
-			info, err := e.Info()
-			if err != nil {
-				log.Fatalf("%v", err)
+		if e.IsDir {
+			items = append(items, Folder{path: JoinPath(dir, name)})
+			continue
+		}
+		if len(name) > 3 && strings.HasSuffix(name, ".md") {
+			notes = append(notes, loadNote(s, JoinPath(dir, name), e.ModTime))
+		}
+	}
+	return append(items, notes...), nil
+}
+
+// WalkNotes calls fn for every markdown note in the store, descending into
+// folders. Hidden files and folders are skipped.
+func WalkNotes(s store.NoteStore, fn func(name string, info store.Info) error) error {
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		entries, err := s.List(dir)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if strings.HasPrefix(e.Name, ".") {
+				continue
+			}
+			name := JoinPath(dir, e.Name)
+			if e.IsDir {
+				if err := walk(name); err != nil {
+					return err
+				}
+				continue
+			}
+			if strings.HasSuffix(e.Name, ".md") {
+				if err := fn(name, e); err != nil {
+					return err
+				}
 			}
-			modTime := info.ModTime().Format("2006-01-02 15:04")
-			items = append(items, Note{
-				title: name[:len(name)-3],
-				desc:  modTime,
-			})
 		}
+		return nil
 	}
-	return items
-}
\ No newline at end of file
+	return walk("")
+}
+
+// AllNotes returns every note in the store, walking folders recursively.
+func AllNotes(s store.NoteStore) ([]Note, error) {
+	var notes []Note
+	err := WalkNotes(s, func(name string, info store.Info) error {
+		notes = append(notes, loadNote(s, name, info.ModTime))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return notes, nil
+}
+
+// loadNote reads and parses a stored note. An unreadable file still yields
+// a Note without metadata so listing never fails because of one bad file.
+func loadNote(s store.NoteStore, name string, modTime time.Time) Note {
+	content, err := s.Read(name)
+	if err != nil {
+		return NewNote(name, modTime, FrontMatter{})
+	}
+	return ParseNote(name, modTime, content)
+}
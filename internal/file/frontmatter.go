@@ -0,0 +1,142 @@
+package file
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter is the optional YAML block at the top of a note, delimited
+// by "---" lines. Keys other than the known ones end up in Properties.
+type FrontMatter struct {
+	Title      string         `yaml:"title,omitempty"`
+	Tags       StringList     `yaml:"tags,omitempty"`
+	Aliases    StringList     `yaml:"aliases,omitempty"`
+	Created    time.Time      `yaml:"created,omitempty"`
+	Updated    time.Time      `yaml:"updated,omitempty"`
+	Properties map[string]any `yaml:",inline"`
+}
+
+// StringList accepts either a YAML sequence or a single comma separated
+// string, so both "tags: [a, b]" and "tags: a, b" work.
+type StringList []string
+
+func (l *StringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*l = nil
+		for _, part := range strings.Split(value.Value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				*l = append(*l, part)
+			}
+		}
+		return nil
+	}
+	var items []string
+	if err := value.Decode(&items); err != nil {
+		return err
+	}
+	*l = items
+	return nil
+}
+
+// SplitFrontMatter separates the front matter block from the body of a
+// note. Content without a block is returned unchanged as the body; a block
+// that is not valid YAML is reported as an error.
+func SplitFrontMatter(content []byte) (FrontMatter, []byte, error) {
+	var fm FrontMatter
+	block, body, ok := cutFrontMatter(content)
+	if !ok {
+		return fm, content, nil
+	}
+	if err := yaml.Unmarshal(block, &fm); err != nil {
+		return FrontMatter{}, content, err
+	}
+	return fm, body, nil
+}
+
+func cutFrontMatter(content []byte) (block, body []byte, ok bool) {
+	first, rest, found := bytes.Cut(content, []byte("\n"))
+	if !found || string(bytes.TrimRight(first, "\r")) != "---" {
+		return nil, content, false
+	}
+	offset := 0
+	for offset <= len(rest) {
+		line, _, more := bytes.Cut(rest[offset:], []byte("\n"))
+		trimmed := string(bytes.TrimRight(line, "\r"))
+		if trimmed == "---" || trimmed == "..." {
+			end := offset + len(line)
+			if more {
+				end++
+			}
+			return rest[:offset], rest[end:], true
+		}
+		if !more {
+			break
+		}
+		offset += len(line) + 1
+	}
+	return nil, content, false
+}
+
+// NewNoteContent returns the initial content of a freshly created note.
+// title is only written when set, i.e. when it differs from the file name.
+func NewNoteContent(title string, created time.Time) string {
+	head := "---\n"
+	if title != "" {
+		quoted, err := yaml.Marshal(title)
+		if err == nil {
+			head += "title: " + strings.TrimSpace(string(quoted)) + "\n"
+		}
+	}
+	return head + "created: " + created.Format(time.RFC3339) + "\n---\n\n"
+}
+
+// SetTitle returns content with the front matter title set to title, or
+// taken out when title is empty. The other lines of the block are kept as
+// they are, and a note only gets a block for a title or loses an emptied one.
+func SetTitle(content []byte, title string) []byte {
+	var titleLine string
+	if title != "" {
+		quoted, err := yaml.Marshal(title)
+		if err != nil {
+			return content
+		}
+		titleLine = "title: " + strings.TrimSpace(string(quoted))
+	}
+	block, body, ok := cutFrontMatter(content)
+	if !ok {
+		if titleLine == "" {
+			return content
+		}
+		return append([]byte("---\n"+titleLine+"\n---\n\n"), content...)
+	}
+
+	var lines []string
+	if titleLine != "" {
+		lines = append(lines, titleLine)
+	}
+	inTitle := false
+	for _, line := range strings.SplitAfter(string(block), "\n") {
+		if line == "" {
+			continue
+		}
+		// A title spread over several lines continues with indented ones.
+		if inTitle && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			continue
+		}
+		inTitle = strings.HasPrefix(line, "title:")
+		if !inTitle {
+			lines = append(lines, strings.TrimSuffix(line, "\n"))
+		}
+	}
+	if len(lines) == 0 {
+		return bytes.TrimPrefix(body, []byte("\n"))
+	}
+	head := "---\n"
+	for _, line := range lines {
+		head += line + "\n"
+	}
+	return append([]byte(head+"---\n"), body...)
+}
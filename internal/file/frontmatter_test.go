@@ -0,0 +1,168 @@
+package file
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	t.Run("parses known fields and properties", func(t *testing.T) {
+		content := "---\ntitle: Weekly Plan\ntags: [work, planning]\naliases:\n  - plan\ncreated: 2024-01-15T10:30:00Z\nupdated: 2024-01-16\nstatus: draft\n---\n# Body\n"
+
+		fm, body, err := SplitFrontMatter([]byte(content))
+		if err != nil {
+			t.Fatalf("SplitFrontMatter failed: %v", err)
+		}
+		if fm.Title != "Weekly Plan" {
+			t.Errorf("Expected title 'Weekly Plan', got '%s'", fm.Title)
+		}
+		if len(fm.Tags) != 2 || fm.Tags[0] != "work" || fm.Tags[1] != "planning" {
+			t.Errorf("Unexpected tags: %v", fm.Tags)
+		}
+		if len(fm.Aliases) != 1 || fm.Aliases[0] != "plan" {
+			t.Errorf("Unexpected aliases: %v", fm.Aliases)
+		}
+		if !fm.Created.Equal(time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)) {
+			t.Errorf("Unexpected created time: %v", fm.Created)
+		}
+		if fm.Updated.Format("2006-01-02") != "2024-01-16" {
+			t.Errorf("Unexpected updated time: %v", fm.Updated)
+		}
+		if fm.Properties["status"] != "draft" {
+			t.Errorf("Expected property status=draft, got %v", fm.Properties)
+		}
+		if string(body) != "# Body\n" {
+			t.Errorf("Expected body '# Body\\n', got '%s'", string(body))
+		}
+	})
+
+	t.Run("comma separated tags", func(t *testing.T) {
+		fm, _, err := SplitFrontMatter([]byte("---\ntags: go, notes\n---\n"))
+		if err != nil {
+			t.Fatalf("SplitFrontMatter failed: %v", err)
+		}
+		if len(fm.Tags) != 2 || fm.Tags[1] != "notes" {
+			t.Errorf("Unexpected tags: %v", fm.Tags)
+		}
+	})
+
+	t.Run("content without front matter", func(t *testing.T) {
+		content := "just text\n---\nmore"
+		fm, body, err := SplitFrontMatter([]byte(content))
+		if err != nil {
+			t.Fatalf("SplitFrontMatter failed: %v", err)
+		}
+		if fm.Title != "" || string(body) != content {
+			t.Error("Expected content to be returned unchanged")
+		}
+	})
+
+	t.Run("unterminated block is not front matter", func(t *testing.T) {
+		content := "---\ntitle: open"
+		_, body, err := SplitFrontMatter([]byte(content))
+		if err != nil || string(body) != content {
+			t.Errorf("Expected content unchanged without error, got body '%s', err %v", string(body), err)
+		}
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		if _, _, err := SplitFrontMatter([]byte("---\ntags: [unclosed\n---\n")); err == nil {
+			t.Error("Expected an error for invalid YAML")
+		}
+	})
+}
+
+func TestNoteMetadata(t *testing.T) {
+	modTime := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+
+	t.Run("description falls back to mod time", func(t *testing.T) {
+		note := NewNote("plain.md", modTime, FrontMatter{})
+		if note.Description() != "2024-01-15 10:30" {
+			t.Errorf("Expected description '2024-01-15 10:30', got '%s'", note.Description())
+		}
+	})
+
+	t.Run("description shows metadata", func(t *testing.T) {
+		meta := FrontMatter{
+			Title:   "Plan",
+			Tags:    StringList{"work", "q1"},
+			Created: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			Updated: time.Date(2024, 1, 20, 8, 0, 0, 0, time.UTC),
+			Aliases: StringList{"roadmap"},
+		}
+		note := NewNote("plan.md", modTime, meta)
+
+		expected := "2024-01-20 08:00 • #work #q1 • created 2024-01-01"
+		if note.Description() != expected {
+			t.Errorf("Expected description '%s', got '%s'", expected, note.Description())
+		}
+		if note.Title() != "Plan" {
+			t.Errorf("Expected title 'Plan', got '%s'", note.Title())
+		}
+		if note.FilterValue() != "Plan roadmap" {
+			t.Errorf("Expected FilterValue 'Plan roadmap', got '%s'", note.FilterValue())
+		}
+	})
+
+	t.Run("NotesFiles reads front matter", func(t *testing.T) {
+		s := store.NewMemStore()
+		s.Write("tagged.md", []byte("---\ntags: [go]\n---\nbody"))
+
+		items, err := NotesFiles(s, "")
+		if err != nil {
+			t.Fatalf("NotesFiles failed: %v", err)
+		}
+		note := items[0].(Note)
+		if len(note.Meta().Tags) != 1 || note.Meta().Tags[0] != "go" {
+			t.Errorf("Expected tag 'go', got %v", note.Meta().Tags)
+		}
+	})
+}
+
+func TestNewNoteContent(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	fm, body, err := SplitFrontMatter([]byte(NewNoteContent("", created)))
+	if err != nil {
+		t.Fatalf("SplitFrontMatter failed: %v", err)
+	}
+	if !fm.Created.Equal(created) {
+		t.Errorf("Expected created %v, got %v", created, fm.Created)
+	}
+	if string(body) != "\n" {
+		t.Errorf("Expected an empty body line, got '%s'", string(body))
+	}
+
+	t.Run("with title", func(t *testing.T) {
+		fm, _, err := SplitFrontMatter([]byte(NewNoteContent("Q1: plans / ideas", created)))
+		if err != nil {
+			t.Fatalf("SplitFrontMatter failed: %v", err)
+		}
+		if fm.Title != "Q1: plans / ideas" {
+			t.Errorf("Expected title 'Q1: plans / ideas', got '%s'", fm.Title)
+		}
+	})
+}
+
+func TestSetTitle(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		title   string
+		want    string
+	}{
+		{"replaces the title", "---\ntags: [a]\ntitle: 'Q&A: plans'\ncreated: 2024-01-15T10:30:00Z\n---\n\nbody", "Why?", "---\ntitle: Why?\ntags: [a]\ncreated: 2024-01-15T10:30:00Z\n---\n\nbody"},
+		{"drops the title", "---\ntitle: >-\n  long\n  title\ncreated: 2024-01-15T10:30:00Z\n---\nbody", "", "---\ncreated: 2024-01-15T10:30:00Z\n---\nbody"},
+		{"drops an emptied block", "---\ntitle: Plans\n---\n\nbody", "", "body"},
+		{"adds a block", "body", "A: b", "---\ntitle: 'A: b'\n---\n\nbody"},
+		{"leaves plain notes alone", "body", "", "body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(SetTitle([]byte(tt.content), tt.title)); got != tt.want {
+				t.Errorf("SetTitle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
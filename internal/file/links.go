@@ -0,0 +1,347 @@
+package file
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// wikiLinkRe matches "[[target]]", "[[target#heading]]" and
+// "[[target|alias]]". The first group is the target, the second whatever
+// follows it inside the brackets.
+var wikiLinkRe = regexp.MustCompile(`\[\[([^\[\]|#]+)((?:#[^\[\]|]*)?(?:\|[^\[\]]*)?)\]\]`)
+
+// mdLinkRe matches inline markdown links "[text](destination)". Images are
+// matched too so they can be told apart by the leading "!".
+var mdLinkRe = regexp.MustCompile(`(!?)\[([^\[\]]*)\]\(([^()\s]+)\)`)
+
+// RenameLinks rewrites the links in content, the text of the note stored at
+// from, that point to oldPath so they point to newPath instead. Wiki links
+// are resolved with r, which knows the notes as they were before the
+// rename, so a link to another note of the same name is left alone, as is
+// a link by front matter title or alias, which still holds after the
+// rename; a link by name that would be taken by another note after the
+// rename is written as a path. Markdown links are resolved relative to the
+// folder of from. Fenced code blocks are left alone. It returns the new
+// content and the number of links changed.
+func RenameLinks(content, from, oldPath, newPath string, r *Resolver) (string, int) {
+	oldName, newName := NoteName(oldPath), NoteName(newPath)
+	newRef := strings.TrimSuffix(newPath, ".md")
+	if !strings.Contains(newRef, "/") {
+		newRef = "/" + newRef
+	}
+	dir := ParentDir(from)
+	changed := 0
+
+	lines := strings.Split(content, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		line = wikiLinkRe.ReplaceAllStringFunc(line, func(link string) string {
+			m := wikiLinkRe.FindStringSubmatch(link)
+			target := strings.TrimSpace(m[1])
+			if resolved, byTitle, ok := r.wiki(target, from); !ok || byTitle || resolved != oldPath {
+				return link
+			}
+			if other, byTitle, taken := r.wiki(newName, from); strings.Contains(target, "/") || taken && !byTitle && other != oldPath {
+				target = newRef
+			} else {
+				target = newName
+			}
+			changed++
+			return "[[" + target + m[2] + "]]"
+		})
+		line = mdLinkRe.ReplaceAllStringFunc(line, func(link string) string {
+			m := mdLinkRe.FindStringSubmatch(link)
+			if m[1] == "!" {
+				return link
+			}
+			text, dest := m[2], m[3]
+			target, fragment, ok := resolveLink(dir, dest)
+			if !ok || target != oldPath {
+				return link
+			}
+			if strings.HasPrefix(dest, "/") {
+				dest = "/" + newPath
+			} else {
+				dest = relPath(dir, newPath)
+			}
+			if text == oldName {
+				text = newName
+			}
+			changed++
+			return "[" + text + "](" + strings.ReplaceAll(dest, " ", "%20") + fragment + ")"
+		})
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n"), changed
+}
+
+// NoteName returns the file name of a note path without ".md".
+func NoteName(p string) string {
+	return strings.TrimSuffix(path.Base(p), ".md")
+}
+
+// resolveLink turns a markdown link destination found in a note inside dir
+// into a store path and the "#fragment" that followed it. External links
+// and links to other files than notes are reported as not ok.
+func resolveLink(dir, dest string) (target, fragment string, ok bool) {
+	if strings.Contains(dest, "://") || strings.HasPrefix(dest, "mailto:") {
+		return "", "", false
+	}
+	if i := strings.Index(dest, "#"); i >= 0 {
+		dest, fragment = dest[:i], dest[i:]
+	}
+	if unescaped, err := url.PathUnescape(dest); err == nil {
+		dest = unescaped
+	}
+	if !strings.HasSuffix(dest, ".md") {
+		return "", "", false
+	}
+	if strings.HasPrefix(dest, "/") {
+		target = path.Clean(dest)[1:]
+	} else {
+		target = path.Join(dir, dest)
+	}
+	if target == ".." || strings.HasPrefix(target, "../") {
+		return "", "", false
+	}
+	return target, fragment, true
+}
+
+// relPath returns the path of target as seen from the folder dir.
+func relPath(dir, target string) string {
+	if dir == "" {
+		return target
+	}
+	from := strings.Split(dir, "/")
+	to := strings.Split(target, "/")
+	common := 0
+	for common < len(from) && common < len(to)-1 && from[common] == to[common] {
+		common++
+	}
+	parts := make([]string, 0, len(from)-common+len(to)-common)
+	for range from[common:] {
+		parts = append(parts, "..")
+	}
+	return strings.Join(append(parts, to[common:]...), "/")
+}
+
+// WikiLink is a "[[target#heading|alias]]" link found in a line of a note.
+// Start and End are the byte offsets of the whole link within the line.
+type WikiLink struct {
+	Target     string
+	Heading    string
+	Alias      string
+	Start, End int
+}
+
+// WikiLinks returns the wiki links in line.
+func WikiLinks(line string) []WikiLink {
+	var links []WikiLink
+	for _, loc := range wikiLinkRe.FindAllStringSubmatchIndex(line, -1) {
+		link := WikiLink{Target: strings.TrimSpace(line[loc[2]:loc[3]]), Start: loc[0], End: loc[1]}
+		rest := line[loc[4]:loc[5]]
+		if before, alias, ok := strings.Cut(rest, "|"); ok {
+			rest, link.Alias = before, strings.TrimSpace(alias)
+		}
+		link.Heading = strings.TrimSpace(strings.TrimPrefix(rest, "#"))
+		links = append(links, link)
+	}
+	return links
+}
+
+// WikiLinkAt returns the wiki link in line under the byte offset col. A
+// cursor right after the closing brackets still counts as on the link.
+func WikiLinkAt(line string, col int) (WikiLink, bool) {
+	links := WikiLinks(line)
+	for _, link := range links {
+		if col >= link.Start && col < link.End {
+			return link, true
+		}
+	}
+	for _, link := range links {
+		if col == link.End {
+			return link, true
+		}
+	}
+	return WikiLink{}, false
+}
+
+// ResolveWikiLink finds the note among notes, given as store paths, that a
+// wiki link target in the note at from points to. See Resolver.
+func ResolveWikiLink(target, from string, notes []string) (string, bool) {
+	return NewResolver(notes).Wiki(target, from)
+}
+
+// Resolver finds the notes that links point to among a set of notes.
+type Resolver struct {
+	notes   map[string]bool
+	byRef   map[string]string
+	byName  map[string][]string
+	byTitle map[string][]string
+}
+
+// NewResolver prepares the resolution of links to notes, given as store
+// paths.
+func NewResolver(notes []string) *Resolver {
+	r := &Resolver{
+		notes:   make(map[string]bool, len(notes)),
+		byRef:   make(map[string]string, len(notes)),
+		byName:  make(map[string][]string),
+		byTitle: make(map[string][]string),
+	}
+	sorted := append([]string(nil), notes...)
+	sortPaths(sorted)
+	for _, name := range sorted {
+		r.notes[name] = true
+		ref := strings.ToLower(strings.TrimSuffix(name, ".md"))
+		if _, ok := r.byRef[ref]; !ok {
+			r.byRef[ref] = name
+		}
+		key := strings.ToLower(NoteName(name))
+		r.byName[key] = append(r.byName[key], name)
+	}
+	return r
+}
+
+// NewNoteResolver is NewResolver for parsed notes, whose front matter
+// titles and aliases can be linked to as well.
+func NewNoteResolver(notes []Note) *Resolver {
+	paths := make([]string, len(notes))
+	for i, note := range notes {
+		paths[i] = note.Path()
+	}
+	r := NewResolver(paths)
+	for _, note := range notes {
+		r.AddNames(note.Path(), append([]string{note.Meta().Title}, note.Meta().Aliases...)...)
+	}
+	return r
+}
+
+// AddNames lets wiki links name the note at name by names as well, such as
+// its front matter title and aliases. File names take precedence: a name is
+// only looked up here when no note has it as file name.
+func (r *Resolver) AddNames(name string, names ...string) {
+	for _, n := range names {
+		key := strings.ToLower(strings.TrimSpace(n))
+		if key == "" || slices.Contains(r.byTitle[key], name) {
+			continue
+		}
+		r.byTitle[key] = append(r.byTitle[key], name)
+		sortPaths(r.byTitle[key])
+	}
+}
+
+// sortPaths orders note paths shortest first, then by name.
+func sortPaths(paths []string) {
+	sort.Slice(paths, func(i, j int) bool {
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) < len(paths[j])
+		}
+		return paths[i] < paths[j]
+	})
+}
+
+// Wiki returns the note a wiki link target in the note at from points to.
+// As with RenameLinks a target containing a folder names the path of a note
+// without ".md" and any other target its file name; failing that, a name
+// added with AddNames is looked up. Case is ignored. When several notes
+// share the name, the one in the folder of from wins, then the shortest
+// path.
+func (r *Resolver) Wiki(target, from string) (string, bool) {
+	name, _, ok := r.wiki(target, from)
+	return name, ok
+}
+
+// wiki is Wiki, also reporting whether the target was found among the
+// names added with AddNames rather than as a path or file name.
+func (r *Resolver) wiki(target, from string) (name string, byTitle, ok bool) {
+	target = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(target), ".md"))
+	if strings.Contains(target, "/") {
+		if name, ok := r.byRef[strings.TrimPrefix(target, "/")]; ok {
+			return name, false, true
+		}
+	}
+	candidates := r.byName[target]
+	if len(candidates) == 0 {
+		candidates, byTitle = r.byTitle[target], true
+	}
+	if len(candidates) == 0 {
+		return "", false, false
+	}
+	dir := ParentDir(from)
+	for _, name := range candidates {
+		if ParentDir(name) == dir {
+			return name, byTitle, true
+		}
+	}
+	return candidates[0], byTitle, true
+}
+
+// Resolve returns the note ref, found in the note at from, points to.
+func (r *Resolver) Resolve(ref Reference, from string) (string, bool) {
+	if ref.Wiki {
+		return r.Wiki(ref.Target, from)
+	}
+	return ref.Target, r.notes[ref.Target]
+}
+
+// Reference is a link from a note to another note. Wiki links keep their
+// target as written, since the note they point to depends on the other
+// notes; markdown links are resolved to a store path right away.
+type Reference struct {
+	Target string
+	Wiki   bool
+	// Line is the 1-based line of the link and Text that line, with Start
+	// and End the byte offsets of the link within it.
+	Line       int
+	Text       string
+	Start, End int
+}
+
+// References returns the links to other notes in content, the text of the
+// note stored at from. Fenced code blocks are skipped, as are images and
+// external links.
+func References(content, from string) []Reference {
+	var refs []Reference
+	dir := ParentDir(from)
+	inFence := false
+	for i, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		var found []Reference
+		for _, link := range WikiLinks(line) {
+			found = append(found, Reference{Target: link.Target, Wiki: true, Start: link.Start, End: link.End})
+		}
+		for _, loc := range mdLinkRe.FindAllStringSubmatchIndex(line, -1) {
+			if loc[3] > loc[2] {
+				continue // an image
+			}
+			target, _, ok := resolveLink(dir, line[loc[6]:loc[7]])
+			if ok {
+				found = append(found, Reference{Target: target, Start: loc[0], End: loc[1]})
+			}
+		}
+		sort.SliceStable(found, func(a, b int) bool { return found[a].Start < found[b].Start })
+		for _, ref := range found {
+			ref.Line, ref.Text = i+1, line
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
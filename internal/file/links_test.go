@@ -0,0 +1,241 @@
+package file
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRenameLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		from     string
+		notes    []string
+		oldPath  string
+		newPath  string
+		expected string
+		changed  int
+	}{
+		{
+			name:     "wiki link by name",
+			content:  "See [[plan]] and [[Plan|the plan]] or [[plan#goals]].",
+			from:     "index.md",
+			oldPath:  "work/plan.md",
+			newPath:  "work/roadmap.md",
+			expected: "See [[roadmap]] and [[roadmap|the plan]] or [[roadmap#goals]].",
+			changed:  3,
+		},
+		{
+			name:     "wiki link by path",
+			content:  "[[work/plan]] [[other]]",
+			from:     "index.md",
+			oldPath:  "work/plan.md",
+			newPath:  "work/roadmap.md",
+			expected: "[[work/roadmap]] [[other]]",
+			changed:  1,
+		},
+		{
+			name:     "markdown link in same folder",
+			content:  "[plan](plan.md) and [the plan](plan.md#goals)",
+			from:     "work/index.md",
+			oldPath:  "work/plan.md",
+			newPath:  "work/road map.md",
+			expected: "[road map](road%20map.md) and [the plan](road%20map.md#goals)",
+			changed:  2,
+		},
+		{
+			name:     "markdown link from another folder",
+			content:  "[x](../work/plan.md) [y](/work/plan.md) [z](work/plan.md)",
+			from:     "personal/index.md",
+			oldPath:  "work/plan.md",
+			newPath:  "work/q1 plan.md",
+			expected: "[x](../work/q1%20plan.md) [y](/work/q1%20plan.md) [z](work/plan.md)",
+			changed:  2,
+		},
+		{
+			name:     "escaped destination",
+			content:  "[notes](my%20plan.md)",
+			from:     "index.md",
+			oldPath:  "my plan.md",
+			newPath:  "plan.md",
+			expected: "[notes](plan.md)",
+			changed:  1,
+		},
+		{
+			name:     "leaves other links alone",
+			content:  "![img](plan.md) [site](https://example.com/plan.md) [other](other.md)",
+			from:     "index.md",
+			oldPath:  "plan.md",
+			newPath:  "roadmap.md",
+			expected: "![img](plan.md) [site](https://example.com/plan.md) [other](other.md)",
+			changed:  0,
+		},
+		{
+			name:     "skips fenced code",
+			content:  "```\n[[plan]]\n```\n[[plan]]",
+			from:     "index.md",
+			oldPath:  "plan.md",
+			newPath:  "roadmap.md",
+			expected: "```\n[[plan]]\n```\n[[roadmap]]",
+			changed:  1,
+		},
+		{
+			name:     "leaves a note of the same name in another folder alone",
+			content:  "[[plan]] and [[a/plan]]",
+			from:     "b/ref.md",
+			notes:    []string{"b/plan.md"},
+			oldPath:  "a/plan.md",
+			newPath:  "a/roadmap.md",
+			expected: "[[plan]] and [[a/roadmap]]",
+			changed:  1,
+		},
+		{
+			name:     "same name from the folder of the note",
+			content:  "[[plan]]",
+			from:     "a/ref.md",
+			notes:    []string{"b/plan.md"},
+			oldPath:  "a/plan.md",
+			newPath:  "a/roadmap.md",
+			expected: "[[roadmap]]",
+			changed:  1,
+		},
+		{
+			name:     "new name taken by another note",
+			content:  "[[plan]] [[/plan]]",
+			from:     "index.md",
+			notes:    []string{"work/roadmap.md"},
+			oldPath:  "plan.md",
+			newPath:  "roadmap.md",
+			expected: "[[/roadmap]] [[/roadmap]]",
+			changed:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewResolver(append([]string{tt.from, tt.oldPath}, tt.notes...))
+			got, changed := RenameLinks(tt.content, tt.from, tt.oldPath, tt.newPath, r)
+			if got != tt.expected {
+				t.Errorf("RenameLinks() = %q, want %q", got, tt.expected)
+			}
+			if changed != tt.changed {
+				t.Errorf("Expected %d links changed, got %d", tt.changed, changed)
+			}
+		})
+	}
+}
+
+func TestRenameLinks_ByTitle(t *testing.T) {
+	r := NewResolver([]string{"index.md", "faq-2024.md"})
+	r.AddNames("faq-2024.md", "FAQ", "Q&A: plans")
+
+	content := "[[FAQ]], [[Q&A: plans|questions]] and [[faq-2024#intro]]"
+	got, changed := RenameLinks(content, "index.md", "faq-2024.md", "faq.md", r)
+	// Links by title and alias still find the note after the rename.
+	want := "[[FAQ]], [[Q&A: plans|questions]] and [[faq#intro]]"
+	if got != want || changed != 1 {
+		t.Errorf("RenameLinks() = %q, %d, want %q, 1", got, changed, want)
+	}
+
+	// A title another note has does not take the new name.
+	r.AddNames("index.md", "roadmap")
+	if got, _ := RenameLinks("[[faq-2024]]", "index.md", "faq-2024.md", "roadmap.md", r); got != "[[roadmap]]" {
+		t.Errorf("Expected the new file name to win over a title, got %q", got)
+	}
+}
+
+func TestWikiLinks(t *testing.T) {
+	line := "See [[Plan|the plan]], [[work/ideas#Next steps]] and [[ notes ]]."
+	want := []WikiLink{
+		{Target: "Plan", Alias: "the plan", Start: 4, End: 21},
+		{Target: "work/ideas", Heading: "Next steps", Start: 23, End: 48},
+		{Target: "notes", Start: 53, End: 64},
+	}
+	got := WikiLinks(line)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WikiLinks() = %+v, want %+v", got, want)
+	}
+	if links := WikiLinks("[[]] [not a link] [[a|b]c]]"); len(links) != 0 {
+		t.Errorf("Expected no links, got %+v", links)
+	}
+}
+
+func TestWikiLinkAt(t *testing.T) {
+	line := "[[a]][[b]] and [[c]]"
+	tests := []struct {
+		col    int
+		target string
+		ok     bool
+	}{
+		{0, "a", true},
+		{4, "a", true},
+		{5, "b", true},
+		{10, "b", true},
+		{12, "", false},
+		{20, "c", true},
+	}
+	for _, tt := range tests {
+		link, ok := WikiLinkAt(line, tt.col)
+		if ok != tt.ok || link.Target != tt.target {
+			t.Errorf("WikiLinkAt(%d) = %q, %v, want %q, %v", tt.col, link.Target, ok, tt.target, tt.ok)
+		}
+	}
+}
+
+func TestResolveWikiLink(t *testing.T) {
+	notes := []string{"plan.md", "work/plan.md", "work/deep/Ideas.md", "personal/ideas.md", "z/ideas.md"}
+	tests := []struct {
+		name   string
+		target string
+		from   string
+		want   string
+		ok     bool
+	}{
+		{"same folder wins", "plan", "work/index.md", "work/plan.md", true},
+		{"shortest path", "plan", "personal/index.md", "plan.md", true},
+		{"ignores case", "IDEAS", "index.md", "z/ideas.md", true},
+		{"by path", "work/deep/ideas", "index.md", "work/deep/Ideas.md", true},
+		{"with extension", "plan.md", "index.md", "plan.md", true},
+		{"missing", "roadmap", "index.md", "", false},
+		{"missing path", "work/ideas", "index.md", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ResolveWikiLink(tt.target, tt.from, notes)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ResolveWikiLink() = %q, %v, want %q, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestNoteResolver(t *testing.T) {
+	parse := func(name, content string) Note {
+		return ParseNote(name, time.Time{}, []byte(content))
+	}
+	r := NewNoteResolver([]Note{
+		parse("Q&A- plans.md", "---\ntitle: 'Q&A: plans'\naliases: [faq, plan]\n---\n"),
+		parse("work/Q1- goals.md", "---\ntitle: 'Q1: goals / ideas'\n---\n"),
+		parse("plan.md", "---\ntitle: Other\n---\n"),
+	})
+	tests := []struct {
+		target string
+		want   string
+		ok     bool
+	}{
+		{"Q&A: plans", "Q&A- plans.md", true},
+		{"q&a- plans", "Q&A- plans.md", true},
+		{"FAQ", "Q&A- plans.md", true},
+		{"Q1: goals / ideas", "work/Q1- goals.md", true},
+		{"plan", "plan.md", true}, // the file name beats the alias
+		{"Other", "plan.md", true},
+		{"Q&A", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := r.Wiki(tt.target, "index.md")
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Wiki(%q) = %q, %v, want %q, %v", tt.target, got, ok, tt.want, tt.ok)
+		}
+	}
+}
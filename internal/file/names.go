@@ -0,0 +1,142 @@
+package file
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrInvalidName is returned for names that cannot be used for a note or
+// folder, such as "..", hidden names or reserved device names.
+var ErrInvalidName = errors.New("invalid name")
+
+// maxNameBytes leaves room below the usual 255 byte limit for the ".md"
+// extension and the temporary files used while saving.
+const maxNameBytes = 200
+
+// unsafeChars cannot be used in file names on at least one supported
+// platform; "/" would create folders and is replaced as well.
+const unsafeChars = `/\:*?"<>|`
+
+// reservedNames are device names on Windows, invalid with any extension.
+var reservedNames = map[string]bool{
+	"con": true, "prn": true, "aux": true, "nul": true,
+	"com1": true, "com2": true, "com3": true, "com4": true, "com5": true,
+	"com6": true, "com7": true, "com8": true, "com9": true,
+	"lpt1": true, "lpt2": true, "lpt3": true, "lpt4": true, "lpt5": true,
+	"lpt6": true, "lpt7": true, "lpt8": true, "lpt9": true,
+}
+
+// SafeName turns a name typed by the user into a file or folder name that
+// is valid everywhere: unsafe and control characters become "-", leading
+// dots and trailing dots and spaces are dropped, and the result is capped
+// in length. Names containing a ".." segment, or that end up empty or
+// reserved, are rejected.
+func SafeName(name string) (string, error) {
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if strings.TrimSpace(part) == ".." {
+			return "", fmt.Errorf("%w: %q points outside its folder", ErrInvalidName, name)
+		}
+	}
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(name) {
+		if unicode.IsControl(r) || strings.ContainsRune(unsafeChars, r) {
+			r = '-'
+		}
+		b.WriteRune(r)
+	}
+	safe := strings.TrimLeft(b.String(), ". ")
+	if len(safe) > maxNameBytes {
+		safe = safe[:maxNameBytes]
+		for !utf8.ValidString(safe) {
+			safe = safe[:len(safe)-1]
+		}
+	}
+	safe = strings.TrimRight(safe, ". ")
+	if err := checkName(safe); err != nil {
+		return "", fmt.Errorf("%w: %q", err, name)
+	}
+	return safe, nil
+}
+
+// checkName reports whether name can be used as is for a path segment.
+func checkName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("%w: name is empty", ErrInvalidName)
+	case name == "." || name == "..":
+		return fmt.Errorf("%w: %s is not allowed", ErrInvalidName, name)
+	case strings.HasPrefix(name, "."):
+		return fmt.Errorf("%w: names cannot start with a dot", ErrInvalidName)
+	case strings.ContainsAny(name, unsafeChars):
+		return fmt.Errorf("%w: names cannot contain any of %s", ErrInvalidName, unsafeChars)
+	case strings.HasSuffix(name, " ") || strings.HasSuffix(name, "."):
+		return fmt.Errorf("%w: names cannot end with a space or dot", ErrInvalidName)
+	case len(name) > maxNameBytes+len(".md"):
+		return fmt.Errorf("%w: name is too long", ErrInvalidName)
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("%w: names cannot contain control characters", ErrInvalidName)
+		}
+	}
+	base, _, _ := strings.Cut(name, ".")
+	if reservedNames[strings.ToLower(strings.TrimSpace(base))] {
+		return fmt.Errorf("%w: %s is a reserved name", ErrInvalidName, base)
+	}
+	return nil
+}
+
+// ResolvePath joins a folder of the store and a file name into a store
+// path. Every segment of dir and name must already be a valid name, so the
+// result always stays inside the store and never points at hidden files.
+func ResolvePath(dir, name string) (string, error) {
+	if dir != "" {
+		for _, part := range strings.Split(dir, "/") {
+			if err := checkName(part); err != nil {
+				return "", fmt.Errorf("folder %q: %w", dir, err)
+			}
+		}
+	}
+	if err := checkName(name); err != nil {
+		return "", err
+	}
+	return JoinPath(dir, name), nil
+}
+
+// NotePath resolves the path of a note titled title inside dir. The
+// returned title is empty when the file name already shows the title, and
+// otherwise holds the title to keep in the note's front matter.
+func NotePath(dir, title string) (notePath, keepTitle string, err error) {
+	name, err := SafeName(strings.TrimSuffix(strings.TrimSpace(title), ".md"))
+	if err != nil {
+		return "", "", err
+	}
+	notePath, err = ResolvePath(dir, name+".md")
+	if err != nil {
+		return "", "", err
+	}
+	if name != strings.TrimSpace(title) && name+".md" != strings.TrimSpace(title) {
+		keepTitle = strings.TrimSpace(title)
+	}
+	return notePath, keepTitle, nil
+}
+
+// FolderPath resolves the path of a folder named name inside dir.
+func FolderPath(dir, name string) (string, error) {
+	safe, err := SafeName(name)
+	if err != nil {
+		return "", err
+	}
+	return ResolvePath(dir, safe)
+}
+
+// ValidPath reports whether p, a slash separated store path, only consists
+// of valid names.
+func ValidPath(p string) error {
+	_, err := ResolvePath(ParentDir(p), path.Base(p))
+	return err
+}
@@ -0,0 +1,94 @@
+package file
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSafeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"plain", "Meeting notes", "Meeting notes", false},
+		{"trims spaces", "  idea  ", "idea", false},
+		{"unsafe characters", `Q1: plan/ideas?`, "Q1- plan-ideas-", false},
+		{"hidden name", ".bashrc", "bashrc", false},
+		{"trailing dots", "draft...", "draft", false},
+		{"unicode", "Café ☕", "Café ☕", false},
+		{"traversal", "../../.bashrc", "", true},
+		{"windows traversal", `..\secrets`, "", true},
+		{"only dots", "...", "", true},
+		{"empty", "   ", "", true},
+		{"reserved", "CON", "", true},
+		{"reserved with extension", "nul.txt", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SafeName(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidName) {
+					t.Errorf("SafeName(%q): expected ErrInvalidName, got %q, %v", tt.input, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SafeName(%q) failed: %v", tt.input, err)
+			}
+			if got != tt.expected {
+				t.Errorf("SafeName(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+
+	t.Run("caps length on a rune boundary", func(t *testing.T) {
+		got, err := SafeName(strings.Repeat("é", 150))
+		if err != nil {
+			t.Fatalf("SafeName failed: %v", err)
+		}
+		if len(got) > maxNameBytes || !strings.HasPrefix(strings.Repeat("é", 150), got) {
+			t.Errorf("Expected a truncated name of at most %d bytes, got %d", maxNameBytes, len(got))
+		}
+	})
+}
+
+func TestResolvePath(t *testing.T) {
+	tests := []struct {
+		dir, name string
+		expected  string
+		wantErr   bool
+	}{
+		{"", "note.md", "note.md", false},
+		{"work/2024", "note.md", "work/2024/note.md", false},
+		{"", "../note.md", "", true},
+		{"work/..", "note.md", "", true},
+		{".history", "note.md", "", true},
+		{"", "a/b.md", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ResolvePath(tt.dir, tt.name)
+		if (err != nil) != tt.wantErr || got != tt.expected {
+			t.Errorf("ResolvePath(%q, %q) = %q, %v", tt.dir, tt.name, got, err)
+		}
+	}
+}
+
+func TestNotePath(t *testing.T) {
+	path, title, err := NotePath("work", "Plan")
+	if err != nil || path != "work/Plan.md" || title != "" {
+		t.Errorf("NotePath(work, Plan) = %q, %q, %v", path, title, err)
+	}
+
+	path, title, err = NotePath("", "Q1: plan/ideas")
+	if err != nil || path != "Q1- plan-ideas.md" || title != "Q1: plan/ideas" {
+		t.Errorf("Expected sanitized path and kept title, got %q, %q, %v", path, title, err)
+	}
+
+	if _, _, err := NotePath("", "../../etc/passwd"); err == nil {
+		t.Error("Expected traversal to be rejected")
+	}
+}
@@ -0,0 +1,117 @@
+package file
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// inlineTagRe matches "#tag" at the start of a line or after whitespace.
+// Headings ("# Title") never match because a tag cannot start with a space.
+var inlineTagRe = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_/-]+)`)
+
+// InlineTags extracts #tags from a note body, ignoring fenced code blocks
+// and purely numeric tags such as issue references.
+func InlineTags(body string) []string {
+	var tags []string
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, m := range inlineTagRe.FindAllStringSubmatch(line, -1) {
+			if strings.Trim(m[1], "0123456789") != "" {
+				tags = append(tags, m[1])
+			}
+		}
+	}
+	return tags
+}
+
+// NormalizeTag lowercases a tag and strips a leading "#".
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// MergeTags normalizes and de-duplicates tags, keeping first-seen order.
+func MergeTags(lists ...[]string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, l := range lists {
+		for _, tag := range l {
+			tag = NormalizeTag(tag)
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// TagIndex maps each normalized tag to the notes carrying it.
+type TagIndex map[string][]Note
+
+func BuildTagIndex(notes []Note) TagIndex {
+	index := make(TagIndex)
+	for _, n := range notes {
+		for _, tag := range n.Tags() {
+			index[tag] = append(index[tag], n)
+		}
+	}
+	return index
+}
+
+// Tag is a list entry in the tag browser.
+type Tag struct {
+	Name  string
+	Count int
+}
+
+func (t Tag) Title() string       { return "#" + t.Name }
+func (t Tag) Description() string { return fmt.Sprintf("%d %s", t.Count, plural(t.Count, "note", "notes")) }
+func (t Tag) FilterValue() string { return t.Name }
+
+// Items returns the tags as list items, most used first.
+func (idx TagIndex) Items() []list.Item {
+	tags := make([]Tag, 0, len(idx))
+	for name, notes := range idx {
+		tags = append(tags, Tag{Name: name, Count: len(notes)})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Name < tags[j].Name
+	})
+	items := make([]list.Item, len(tags))
+	for i, t := range tags {
+		items[i] = t
+	}
+	return items
+}
+
+// NoteItems returns the notes tagged with tag as list items.
+func (idx TagIndex) NoteItems(tag string) []list.Item {
+	notes := idx[NormalizeTag(tag)]
+	items := make([]list.Item, len(notes))
+	for i, n := range notes {
+		items[i] = n
+	}
+	return items
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,85 @@
+package file
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+func TestInlineTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []string
+	}{
+		{"simple", "buy milk #todo", []string{"todo"}},
+		{"start of line", "#idea for later", []string{"idea"}},
+		{"nested tag", "see #project/totion", []string{"project/totion"}},
+		{"heading is not a tag", "# Heading\n## Sub", nil},
+		{"numbers only", "fixes #123", nil},
+		{"inside word", "issue#abc and a url http://x.io/#frag", nil},
+		{"code block", "```\n#include <stdio.h>\n```\n#real", []string{"real"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InlineTags(tt.body)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("InlineTags(%q) = %v, want %v", tt.body, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMergeTags(t *testing.T) {
+	got := MergeTags([]string{"Work", "#ideas"}, []string{"work", "todo"})
+	expected := []string{"work", "ideas", "todo"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("MergeTags = %v, want %v", got, expected)
+	}
+}
+
+func TestTagIndex(t *testing.T) {
+	s := store.NewMemStore()
+	s.Write("a.md", []byte("---\ntags: [work]\n---\nplanning #todo"))
+	s.MakeDir("nested")
+	s.Write("nested/b.md", []byte("#work in progress"))
+	s.Write("c.md", []byte("no tags"))
+
+	notes, err := AllNotes(s)
+	if err != nil {
+		t.Fatalf("AllNotes failed: %v", err)
+	}
+	if len(notes) != 3 {
+		t.Fatalf("Expected 3 notes, got %d", len(notes))
+	}
+
+	index := BuildTagIndex(notes)
+
+	items := index.Items()
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 tags, got %d", len(items))
+	}
+	first := items[0].(Tag)
+	if first.Name != "work" || first.Count != 2 {
+		t.Errorf("Expected most used tag 'work' with 2 notes, got %+v", first)
+	}
+	if first.Title() != "#work" || first.Description() != "2 notes" {
+		t.Errorf("Unexpected tag rendering: %s / %s", first.Title(), first.Description())
+	}
+
+	tagged := index.NoteItems("#Work")
+	if len(tagged) != 2 {
+		t.Errorf("Expected 2 notes tagged work, got %d", len(tagged))
+	}
+}
+
+func TestParseNoteTags(t *testing.T) {
+	note := ParseNote("n.md", time.Now(), []byte("---\ntags: [Go]\n---\nlearning #go and #tui"))
+	expected := []string{"go", "tui"}
+	if !reflect.DeepEqual(note.Tags(), expected) {
+		t.Errorf("Tags() = %v, want %v", note.Tags(), expected)
+	}
+}
@@ -0,0 +1,109 @@
+package history
+
+import "strings"
+
+// Op marks how a line differs between two texts.
+type Op byte
+
+const (
+	Equal  Op = ' '
+	Insert Op = '+'
+	Delete Op = '-'
+)
+
+// DiffLine is one line of a line-based diff.
+type DiffLine struct {
+	Op   Op
+	Text string
+}
+
+// Diff returns the line diff turning a into b, using Myers' algorithm so
+// long notes with few changes stay cheap.
+func Diff(a, b string) []DiffLine {
+	x, y := splitLines(a), splitLines(b)
+	n, m := len(x), len(y)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+	offset := max + 1
+	v := make([]int, 2*max+2)
+	var trace [][]int
+	for d := 0; d <= max; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var i int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				i = v[offset+k+1]
+			} else {
+				i = v[offset+k-1] + 1
+			}
+			j := i - k
+			for i < n && j < m && x[i] == y[j] {
+				i++
+				j++
+			}
+			v[offset+k] = i
+			if i >= n && j >= m {
+				return backtrack(trace, x, y, offset)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks the saved frontiers from the end to rebuild the edits.
+func backtrack(trace [][]int, x, y []string, offset int) []DiffLine {
+	var out []DiffLine
+	i, j := len(x), len(y)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := i - j
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevI := v[offset+prevK]
+		prevJ := prevI - prevK
+		for i > prevI && j > prevJ {
+			i--
+			j--
+			out = append(out, DiffLine{Equal, x[i]})
+		}
+		if d > 0 {
+			if i == prevI {
+				j--
+				out = append(out, DiffLine{Insert, y[j]})
+			} else {
+				i--
+				out = append(out, DiffLine{Delete, x[i]})
+			}
+		}
+	}
+	for l, r := 0, len(out)-1; l < r; l, r = l+1, r-1 {
+		out[l], out[r] = out[r], out[l]
+	}
+	return out
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// Stat counts inserted and deleted lines in a diff.
+func Stat(diff []DiffLine) (added, removed int) {
+	for _, l := range diff {
+		switch l.Op {
+		case Insert:
+			added++
+		case Delete:
+			removed++
+		}
+	}
+	return added, removed
+}
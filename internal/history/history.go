@@ -0,0 +1,158 @@
+package history
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+// Dir is the hidden folder in the notes store that keeps snapshots, one
+// sub folder per note path.
+const Dir = ".history"
+
+const stampLayout = "20060102T150405.000000000"
+
+// Policy decides which snapshots survive compaction. Every snapshot newer
+// than KeepAllFor is kept, older ones are thinned to the newest per day
+// until DailyFor, and anything older is dropped. MaxVersions caps the
+// snapshots kept in full, so a burst of saves cannot push out the daily ones.
+type Policy struct {
+	KeepAllFor  time.Duration
+	DailyFor    time.Duration
+	MaxVersions int
+}
+
+var DefaultPolicy = Policy{
+	KeepAllFor:  24 * time.Hour,
+	DailyFor:    90 * 24 * time.Hour,
+	MaxVersions: 200,
+}
+
+// Version is a stored snapshot of a note.
+type Version struct {
+	Note string
+	Time time.Time
+	name string
+}
+
+// History records and reads snapshots of notes.
+type History struct {
+	Store  store.NoteStore
+	Policy Policy
+	Now    func() time.Time
+}
+
+func New(s store.NoteStore) *History {
+	return &History{Store: s, Policy: DefaultPolicy, Now: time.Now}
+}
+
+func versionsDir(note string) string {
+	return path.Join(Dir, note)
+}
+
+// Record stores content as the newest snapshot of note and compacts the
+// older ones. Content identical to the latest snapshot is not stored again.
+func (h *History) Record(note string, content []byte) error {
+	versions, err := h.Versions(note)
+	if err != nil {
+		return err
+	}
+	if len(versions) > 0 {
+		if latest, err := h.Read(versions[0]); err == nil && bytes.Equal(latest, content) {
+			return nil
+		}
+	}
+	if err := h.Store.MakeDir(versionsDir(note)); err != nil {
+		return err
+	}
+	name := h.Now().UTC().Format(stampLayout)
+	if err := h.Store.Write(path.Join(versionsDir(note), name), content); err != nil {
+		return err
+	}
+	return h.Compact(note)
+}
+
+// Versions lists the snapshots of note, newest first.
+func (h *History) Versions(note string) ([]Version, error) {
+	entries, err := h.Store.List(versionsDir(note))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var versions []Version
+	for _, e := range entries {
+		if e.IsDir || strings.HasPrefix(e.Name, ".") {
+			continue
+		}
+		t, err := time.Parse(stampLayout, e.Name)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, Version{Note: note, Time: t, name: e.Name})
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Time.After(versions[j].Time) })
+	return versions, nil
+}
+
+func (h *History) Read(v Version) ([]byte, error) {
+	return h.Store.Read(path.Join(versionsDir(v.Note), v.name))
+}
+
+// Compact applies the retention policy to the snapshots of note.
+func (h *History) Compact(note string) error {
+	versions, err := h.Versions(note)
+	if err != nil {
+		return err
+	}
+	now := h.Now()
+	seenDays := make(map[string]bool)
+	recent := 0
+	for _, v := range versions {
+		age := now.Sub(v.Time)
+		day := v.Time.Local().Format("2006-01-02")
+		keep := false
+		switch {
+		case age <= h.Policy.KeepAllFor:
+			// Past the cap the tier thins out to one snapshot a day too.
+			keep = h.Policy.MaxVersions <= 0 || recent < h.Policy.MaxVersions || !seenDays[day]
+			recent++
+		case age <= h.Policy.DailyFor:
+			keep = !seenDays[day]
+		}
+		if keep {
+			seenDays[day] = true
+			continue
+		}
+		if err := h.Store.Delete(path.Join(versionsDir(note), v.name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Move carries the snapshots of a renamed note over to its new path.
+func (h *History) Move(oldNote, newNote string) error {
+	versions, err := h.Versions(oldNote)
+	if err != nil || len(versions) == 0 {
+		return err
+	}
+	if err := h.Store.MakeDir(versionsDir(newNote)); err != nil {
+		return err
+	}
+	for _, v := range versions {
+		if err := h.Store.Rename(path.Join(versionsDir(oldNote), v.name), path.Join(versionsDir(newNote), v.name)); err != nil {
+			return err
+		}
+	}
+	// Only an empty folder is left behind; failing to remove it is harmless.
+	h.Store.Delete(versionsDir(oldNote))
+	return nil
+}
@@ -0,0 +1,171 @@
+package history
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+// fakeClock returns a History whose clock can be moved by the test.
+func fakeClock(s store.NoteStore, start time.Time) (*History, *time.Time) {
+	now := start
+	h := New(s)
+	h.Now = func() time.Time { return now }
+	return h, &now
+}
+
+func TestHistory_Record(t *testing.T) {
+	s := store.NewMemStore()
+	h, now := fakeClock(s, time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+
+	h.Record("work/plan.md", []byte("v1"))
+	*now = now.Add(time.Minute)
+	h.Record("work/plan.md", []byte("v1"))
+	*now = now.Add(time.Minute)
+	h.Record("work/plan.md", []byte("v2"))
+
+	versions, err := h.Versions("work/plan.md")
+	if err != nil {
+		t.Fatalf("Versions failed: %v", err)
+	}
+	if len(versions) != 2 {
+		t.Fatalf("Expected 2 versions (duplicate skipped), got %d", len(versions))
+	}
+
+	latest, _ := h.Read(versions[0])
+	if string(latest) != "v2" {
+		t.Errorf("Expected newest version first, got '%s'", string(latest))
+	}
+	if !versions[0].Time.Equal(time.Date(2024, 1, 15, 10, 2, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected version time %v", versions[0].Time)
+	}
+
+	if versions, _ := h.Versions("missing.md"); len(versions) != 0 {
+		t.Errorf("Expected no versions for an unknown note, got %d", len(versions))
+	}
+}
+
+func TestHistory_Compact(t *testing.T) {
+	s := store.NewMemStore()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	h, now := fakeClock(s, start)
+	h.Policy = Policy{KeepAllFor: 24 * time.Hour, DailyFor: 10 * 24 * time.Hour, MaxVersions: 100}
+
+	// Three snapshots a day for 20 days.
+	for day := 0; day < 20; day++ {
+		for i := 0; i < 3; i++ {
+			*now = start.Add(time.Duration(day)*24*time.Hour + time.Duration(i)*time.Hour)
+			h.Record("n.md", []byte(fmt.Sprintf("day %d rev %d", day, i)))
+		}
+	}
+
+	versions, _ := h.Versions("n.md")
+	// The last 24h keep all 4 snapshots (3 today, 1 yesterday), the 9 days
+	// before keep one each and anything older than 10 days is gone.
+	if len(versions) != 13 {
+		t.Errorf("Expected 13 versions after compaction, got %d", len(versions))
+	}
+	oldest := versions[len(versions)-1]
+	if now.Sub(oldest.Time) > h.Policy.DailyFor {
+		t.Errorf("Expected versions older than DailyFor to be dropped, oldest is %v", oldest.Time)
+	}
+
+	// MaxVersions only caps the last 24h: the 2 newest are kept, and
+	// yesterday's snapshot stays as the one of its day.
+	h.Policy.MaxVersions = 2
+	h.Compact("n.md")
+	if versions, _ := h.Versions("n.md"); len(versions) != 12 {
+		t.Errorf("Expected MaxVersions to cap the recent snapshots at 2, got %d versions", len(versions))
+	}
+}
+
+func TestHistory_CompactBurst(t *testing.T) {
+	s := store.NewMemStore()
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	h, now := fakeClock(s, start)
+
+	for day := 0; day < 10; day++ {
+		*now = start.Add(time.Duration(day) * 24 * time.Hour)
+		h.Record("n.md", []byte(fmt.Sprintf("day %d", day)))
+	}
+	// One long session autosaving every few seconds.
+	for i := 0; i < 250; i++ {
+		*now = start.Add(10*24*time.Hour + time.Duration(i)*3*time.Second)
+		h.Record("n.md", []byte(fmt.Sprintf("edit %d", i)))
+	}
+
+	versions, _ := h.Versions("n.md")
+	recent := 0
+	for _, v := range versions {
+		if now.Sub(v.Time) <= DefaultPolicy.KeepAllFor {
+			recent++
+		}
+	}
+	if recent != DefaultPolicy.MaxVersions || len(versions) != recent+10 {
+		t.Fatalf("Expected %d recent and 10 daily versions, got %d recent of %d", DefaultPolicy.MaxVersions, recent, len(versions))
+	}
+	oldest, _ := h.Read(versions[len(versions)-1])
+	if string(oldest) != "day 0" {
+		t.Errorf("Expected the daily snapshots to survive the burst, oldest is %q", oldest)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	render := func(diff []DiffLine) string {
+		var lines []string
+		for _, l := range diff {
+			lines = append(lines, string(l.Op)+l.Text)
+		}
+		return strings.Join(lines, "|")
+	}
+
+	tests := []struct {
+		name     string
+		a, b     string
+		expected string
+	}{
+		{"identical", "a\nb", "a\nb", " a| b"},
+		{"insert", "a\nc", "a\nb\nc", " a|+b| c"},
+		{"delete", "a\nb\nc", "a\nc", " a|-b| c"},
+		{"replace", "a\nb", "a\nx", " a|-b|+x"},
+		{"from empty", "", "a", "+a"},
+		{"to empty", "a", "", "-a"},
+		{"both empty", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := render(Diff(tt.a, tt.b)); got != tt.expected {
+				t.Errorf("Diff(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+
+	added, removed := Stat(Diff("a\nb\nc", "a\nx\nc\nd"))
+	if added != 2 || removed != 1 {
+		t.Errorf("Expected +2 -1, got +%d -%d", added, removed)
+	}
+}
+
+func TestHistory_Move(t *testing.T) {
+	s := store.NewMemStore()
+	h, now := fakeClock(s, time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+	h.Record("plan.md", []byte("v1"))
+	*now = now.Add(time.Minute)
+	h.Record("plan.md", []byte("v2"))
+
+	if err := h.Move("plan.md", "work/roadmap.md"); err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if versions, _ := h.Versions("plan.md"); len(versions) != 0 {
+		t.Errorf("Expected no versions left under the old name, got %d", len(versions))
+	}
+	if versions, _ := h.Versions("work/roadmap.md"); len(versions) != 2 {
+		t.Errorf("Expected 2 versions under the new name, got %d", len(versions))
+	}
+	if err := h.Move("missing.md", "other.md"); err != nil {
+		t.Errorf("Expected moving a note without history to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+// Package journal names, finds and fills in daily notes, which live in one
+// folder of the store as YYYY-MM-DD.md.
+package journal
+
+import (
+	"errors"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/file"
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+// DateFormat is the file name of a daily note without ".md".
+const DateFormat = "2006-01-02"
+
+// DefaultTemplate starts daily notes when no template is configured.
+const DefaultTemplate = "# {{title}}\n\n"
+
+// Path returns the path of the daily note of day inside dir.
+func Path(dir string, day time.Time) string {
+	return file.JoinPath(dir, day.Format(DateFormat)+".md")
+}
+
+// Date returns the day of the daily note at p, which must be directly
+// inside dir.
+func Date(dir, p string) (time.Time, bool) {
+	if file.ParentDir(p) != dir || !strings.HasSuffix(p, ".md") {
+		return time.Time{}, false
+	}
+	day, err := time.ParseInLocation(DateFormat, file.NoteName(p), time.Local)
+	return day, err == nil
+}
+
+// Days returns the days with a daily note in dir, newest first. A missing
+// folder has none.
+func Days(s store.NoteStore, dir string) ([]time.Time, error) {
+	entries, err := s.List(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var days []time.Time
+	for _, e := range entries {
+		if e.IsDir {
+			continue
+		}
+		if day, ok := Date(dir, file.JoinPath(dir, e.Name)); ok {
+			days = append(days, day)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].After(days[j]) })
+	return days, nil
+}
+
+// Render fills in a template for the daily note of day. It replaces
+// {{date}} with the date as in the file name, {{title}} with the date
+// written out, {{weekday}} with the name of the day and {{yesterday}} and
+// {{tomorrow}} with the dates of the days around it, ready for [[links]].
+func Render(template string, day time.Time) string {
+	return strings.NewReplacer(
+		"{{date}}", day.Format(DateFormat),
+		"{{title}}", day.Format("Monday, 2 January 2006"),
+		"{{weekday}}", day.Format("Monday"),
+		"{{yesterday}}", day.AddDate(0, 0, -1).Format(DateFormat),
+		"{{tomorrow}}", day.AddDate(0, 0, 1).Format(DateFormat),
+	).Replace(template)
+}
@@ -0,0 +1,77 @@
+package journal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+func day(s string) time.Time {
+	d, err := time.ParseInLocation(DateFormat, s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestPathAndDate(t *testing.T) {
+	if got := Path("journal", day("2026-10-17")); got != "journal/2026-10-17.md" {
+		t.Errorf("Path() = %q", got)
+	}
+	if got := Path("", day("2026-01-02")); got != "2026-01-02.md" {
+		t.Errorf("Path() in the root = %q", got)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"journal/2026-10-17.md", "2026-10-17"},
+		{"journal/2026-02-30.md", ""},
+		{"journal/notes.md", ""},
+		{"other/2026-10-17.md", ""},
+		{"journal/old/2026-10-17.md", ""},
+	}
+	for _, tt := range tests {
+		d, ok := Date("journal", tt.path)
+		if ok != (tt.want != "") || ok && !d.Equal(day(tt.want)) {
+			t.Errorf("Date(%q) = %v, %v, want %q", tt.path, d, ok, tt.want)
+		}
+	}
+}
+
+func TestDays(t *testing.T) {
+	s := store.NewMemStore()
+	if days, err := Days(s, "journal"); err != nil || days != nil {
+		t.Errorf("Expected no days without a folder, got %v, %v", days, err)
+	}
+	s.MakeDir("journal")
+	s.Write("journal/2026-10-15.md", nil)
+	s.Write("journal/2026-10-17.md", nil)
+	s.Write("journal/2026-09-30.md", nil)
+	s.Write("journal/ideas.md", nil)
+	s.Write("journal/2026/2026-10-16.md", nil)
+
+	days, err := Days(s, "journal")
+	if err != nil {
+		t.Fatalf("Days failed: %v", err)
+	}
+	want := []string{"2026-10-17", "2026-10-15", "2026-09-30"}
+	if len(days) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, days)
+	}
+	for i, w := range want {
+		if !days[i].Equal(day(w)) {
+			t.Errorf("Day %d = %s, want %s", i, days[i].Format(DateFormat), w)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	got := Render("# {{title}}\n{{weekday}} {{date}}: [[{{yesterday}}]] [[{{tomorrow}}]] {{other}}", day("2026-03-01"))
+	want := "# Sunday, 1 March 2026\nSunday 2026-03-01: [[2026-02-28]] [[2026-03-02]] {{other}}"
+	if got != want {
+		t.Errorf("Render() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,365 @@
+package keymap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+)
+
+// KeyMap holds the key bindings of the actions that can be remapped in the
+// [keys] table of the config file.
+type KeyMap struct {
+	Quit               key.Binding
+	Back               key.Binding
+	Open               key.Binding
+	NewNote            key.Binding
+	List               key.Binding
+	Search             key.Binding
+	Save               key.Binding
+	History            key.Binding
+	Preview            key.Binding
+	FollowLink         key.Binding
+	LinkBack           key.Binding
+	LinkForward        key.Binding
+	Backlinks          key.Binding
+	Graph              key.Binding
+	Today              key.Binding
+	PrevDay            key.Binding
+	NextDay            key.Binding
+	ToggleAutocomplete key.Binding
+	NextSuggestion     key.Binding
+	AcceptSuggestion   key.Binding
+	NewFolder          key.Binding
+	Rename             key.Binding
+	Trash              key.Binding
+	Delete             key.Binding
+	Tags               key.Binding
+	Journal            key.Binding
+}
+
+// action describes one remappable action. Actions that are not listOnly
+// are also active while typing a note or a name, so they cannot be bound
+// to keys that would otherwise type a character.
+type action struct {
+	name     string
+	keys     []string
+	desc     string
+	listOnly bool
+	binding  func(*KeyMap) *key.Binding
+}
+
+var actions = []action{
+	{"quit", []string{"ctrl+c"}, "Quit Totion", false, func(k *KeyMap) *key.Binding { return &k.Quit }},
+	{"back", []string{"esc"}, "Return to home", false, func(k *KeyMap) *key.Binding { return &k.Back }},
+	{"open", []string{"enter"}, "Open Note / Folder", false, func(k *KeyMap) *key.Binding { return &k.Open }},
+	{"new_note", []string{"ctrl+n"}, "New Note", false, func(k *KeyMap) *key.Binding { return &k.NewNote }},
+	{"list", []string{"ctrl+l"}, "List all Notes", false, func(k *KeyMap) *key.Binding { return &k.List }},
+	{"search", []string{"alt+k"}, "Search", false, func(k *KeyMap) *key.Binding { return &k.Search }},
+	{"save", []string{"ctrl+s"}, "Save Note", false, func(k *KeyMap) *key.Binding { return &k.Save }},
+	{"history", []string{"ctrl+r"}, "History", false, func(k *KeyMap) *key.Binding { return &k.History }},
+	{"preview", []string{"alt+p"}, "Preview / Split", false, func(k *KeyMap) *key.Binding { return &k.Preview }},
+	{"follow_link", []string{"ctrl+]"}, "Follow [[link]]", false, func(k *KeyMap) *key.Binding { return &k.FollowLink }},
+	{"link_back", []string{"ctrl+o"}, "Previous Note", false, func(k *KeyMap) *key.Binding { return &k.LinkBack }},
+	{"link_forward", []string{"ctrl+y"}, "Next Note", false, func(k *KeyMap) *key.Binding { return &k.LinkForward }},
+	{"backlinks", []string{"alt+i"}, "Backlinks", false, func(k *KeyMap) *key.Binding { return &k.Backlinks }},
+	{"graph", []string{"alt+g"}, "Local Graph", false, func(k *KeyMap) *key.Binding { return &k.Graph }},
+	{"today", []string{"alt+t"}, "Today's Note", false, func(k *KeyMap) *key.Binding { return &k.Today }},
+	{"prev_day", []string{"alt+,"}, "Previous Day", false, func(k *KeyMap) *key.Binding { return &k.PrevDay }},
+	{"next_day", []string{"alt+."}, "Next Day", false, func(k *KeyMap) *key.Binding { return &k.NextDay }},
+	{"toggle_autocomplete", []string{"ctrl+t"}, "Toggle Autocomplete", false, func(k *KeyMap) *key.Binding { return &k.ToggleAutocomplete }},
+	{"next_suggestion", []string{"ctrl+g"}, "Next Suggestion", false, func(k *KeyMap) *key.Binding { return &k.NextSuggestion }},
+	{"accept_suggestion", []string{"tab"}, "Accept Suggestion", false, func(k *KeyMap) *key.Binding { return &k.AcceptSuggestion }},
+	{"new_folder", []string{"ctrl+f"}, "New Folder", true, func(k *KeyMap) *key.Binding { return &k.NewFolder }},
+	{"rename", []string{"ctrl+e"}, "Rename Note", true, func(k *KeyMap) *key.Binding { return &k.Rename }},
+	{"trash", []string{"ctrl+x"}, "Trash", true, func(k *KeyMap) *key.Binding { return &k.Trash }},
+	{"delete", []string{"delete", "backspace"}, "Move Note to Trash or delete empty Folder", true, func(k *KeyMap) *key.Binding { return &k.Delete }},
+	{"tags", []string{"#"}, "Browse Tags", true, func(k *KeyMap) *key.Binding { return &k.Tags }},
+	{"journal", []string{"ctrl+d"}, "Journal", true, func(k *KeyMap) *key.Binding { return &k.Journal }},
+}
+
+// passThrough are the actions that leave their keys to the note editor
+// while a note is open, so they may share them with it.
+var passThrough = map[string]bool{"open": true}
+
+// Editor returns the key map of the note editor: the textarea defaults
+// without ctrl+n and ctrl+t, which Totion has always used for new notes and
+// autocomplete. The arrow keys still move between lines.
+func Editor() textarea.KeyMap {
+	k := textarea.DefaultKeyMap
+	k.LineNext = key.NewBinding(key.WithKeys("down"), key.WithHelp("down", "next line"))
+	k.TransposeCharacterBackward = key.NewBinding()
+	return k
+}
+
+// editorKeys maps the keys of the note editor to what they do there.
+func editorKeys() map[string]string {
+	k := Editor()
+	keys := make(map[string]string)
+	for _, b := range []key.Binding{
+		k.CharacterForward, k.CharacterBackward, k.WordForward, k.WordBackward,
+		k.LineNext, k.LinePrevious, k.DeleteWordBackward, k.DeleteWordForward,
+		k.DeleteAfterCursor, k.DeleteBeforeCursor, k.InsertNewline,
+		k.DeleteCharacterBackward, k.DeleteCharacterForward, k.LineStart, k.LineEnd,
+		k.Paste, k.InputBegin, k.InputEnd, k.CapitalizeWordForward,
+		k.LowercaseWordForward, k.UppercaseWordForward, k.TransposeCharacterBackward,
+	} {
+		for _, s := range b.Keys() {
+			keys[s] = b.Help().Desc
+		}
+	}
+	return keys
+}
+
+// Defaults returns the default keys of every action by name, the form used
+// in the config file.
+func Defaults() map[string][]string {
+	keys := make(map[string][]string, len(actions))
+	for _, a := range actions {
+		keys[a.name] = append([]string(nil), a.keys...)
+	}
+	return keys
+}
+
+// Default returns the key map without any overrides.
+func Default() KeyMap {
+	k, _ := New(nil)
+	return k
+}
+
+// New builds the key map from the defaults with the keys in overrides
+// replacing those of the actions they name. An empty list unbinds an
+// action. Unknown actions, unknown key names, printable keys or keys of the
+// note editor for actions active while typing, and keys bound to more than
+// one action are errors.
+func New(overrides map[string][]string) (KeyMap, error) {
+	known := make(map[string]bool, len(actions))
+	for _, a := range actions {
+		known[a.name] = true
+	}
+	for _, name := range sortedNames(overrides) {
+		if !known[name] {
+			return KeyMap{}, fmt.Errorf("unknown action %q", name)
+		}
+	}
+
+	var k KeyMap
+	owner := make(map[string]string)
+	editor := editorKeys()
+	for _, a := range actions {
+		keys, ok := overrides[a.name]
+		if !ok {
+			keys = a.keys
+		}
+		if len(keys) == 0 && a.name == "quit" {
+			return KeyMap{}, fmt.Errorf("quit must have a key")
+		}
+		for _, s := range keys {
+			if !validKey(s) {
+				return KeyMap{}, fmt.Errorf("%s: unknown key %q", a.name, s)
+			}
+			if !a.listOnly && utf8.RuneCountInString(s) == 1 {
+				return KeyMap{}, fmt.Errorf("%s: %q would be typed into notes; use a key with ctrl or alt", a.name, s)
+			}
+			if use, taken := editor[s]; taken && !a.listOnly && !passThrough[a.name] {
+				return KeyMap{}, fmt.Errorf("%s: %q is the editor's %s key", a.name, s, use)
+			}
+			if other, taken := owner[s]; taken {
+				return KeyMap{}, fmt.Errorf("%q is bound to both %s and %s", s, other, a.name)
+			}
+			owner[s] = a.name
+		}
+		b := key.NewBinding(key.WithKeys(keys...), key.WithHelp(displayKeys(keys), a.desc))
+		if len(keys) == 0 {
+			b.Unbind()
+		}
+		*a.binding(&k) = b
+	}
+	return k, nil
+}
+
+func sortedNames(m map[string][]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// namedKeys are the multi-character key names Bubble Tea reports, without
+// modifiers.
+var namedKeys = map[string]bool{
+	"enter": true, "tab": true, "esc": true, "backspace": true, "delete": true,
+	"insert": true, "up": true, "down": true, "left": true, "right": true,
+	"home": true, "end": true, "pgup": true, "pgdown": true,
+}
+
+func init() {
+	for i := 1; i <= 20; i++ {
+		namedKeys[fmt.Sprintf("f%d", i)] = true
+	}
+}
+
+// validKey reports whether s is a key as Bubble Tea names it, such as "a",
+// "ctrl+n", "alt+enter" or "shift+tab".
+func validKey(s string) bool {
+	base := strings.TrimPrefix(s, "alt+")
+	if utf8.RuneCountInString(base) == 1 {
+		return true
+	}
+	ctrl := strings.HasPrefix(base, "ctrl+")
+	base = strings.TrimPrefix(base, "ctrl+")
+	shift := strings.HasPrefix(base, "shift+")
+	base = strings.TrimPrefix(base, "shift+")
+	if namedKeys[base] {
+		return true
+	}
+	// Control characters have no shifted form.
+	return ctrl && !shift && utf8.RuneCountInString(base) == 1
+}
+
+// displayKeys formats keys for the help line, e.g. "Ctrl+N" or
+// "Delete / Backspace".
+func displayKeys(keys []string) string {
+	shown := make([]string, len(keys))
+	for i, k := range keys {
+		shown[i] = displayKey(k)
+	}
+	return strings.Join(shown, " / ")
+}
+
+var keyNames = map[string]string{"pgup": "PgUp", "pgdown": "PgDn", "esc": "Esc"}
+
+func displayKey(k string) string {
+	if utf8.RuneCountInString(k) == 1 {
+		return k
+	}
+	// The last part is the key itself, which may be "+" as in "ctrl++".
+	var mods []string
+	base := k
+	if i := strings.LastIndex(k[:len(k)-1], "+"); i >= 0 {
+		mods, base = strings.Split(k[:i], "+"), k[i+1:]
+	}
+	for i, mod := range mods {
+		mods[i] = capitalize(mod)
+	}
+	switch {
+	case keyNames[base] != "":
+		base = keyNames[base]
+	case utf8.RuneCountInString(base) == 1:
+		base = strings.ToUpper(base)
+	default:
+		base = capitalize(base)
+	}
+	return strings.Join(append(mods, base), "+")
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// help formats "Key: description" entries for the help line, skipping
+// unbound actions. An empty desc uses the binding's own description.
+func help(b key.Binding, desc string) string {
+	if !b.Enabled() {
+		return ""
+	}
+	if desc == "" {
+		desc = b.Help().Desc
+	}
+	return b.Help().Key + ": " + desc
+}
+
+func helpLine(entries ...string) string {
+	kept := entries[:0]
+	for _, e := range entries {
+		if e != "" {
+			kept = append(kept, e)
+		}
+	}
+	return strings.Join(kept, " • ")
+}
+
+// GeneralHelp is shown on the home screen.
+func (k KeyMap) GeneralHelp() string {
+	return helpLine(help(k.NewNote, ""), help(k.List, ""), help(k.Search, ""), help(k.Today, ""), help(k.Back, ""), help(k.Quit, ""))
+}
+
+// EditorHelp is shown while a note is open.
+func (k KeyMap) EditorHelp() string {
+	return helpLine(help(k.NewNote, ""), help(k.List, ""), help(k.Back, ""), help(k.Save, ""), help(k.History, ""), help(k.Preview, ""),
+		help(k.FollowLink, ""), help(k.LinkBack, ""), help(k.LinkForward, ""), help(k.Backlinks, ""), help(k.Graph, ""), help(k.Quit, ""))
+}
+
+// JournalHelp is added to the editor help while a daily note is open.
+func (k KeyMap) JournalHelp() string {
+	return helpLine(help(k.PrevDay, ""), help(k.NextDay, ""), help(k.Today, ""))
+}
+
+// PreviewHelp is shown with the markdown preview.
+func (k KeyMap) PreviewHelp() string {
+	return helpLine("↑/↓ PgUp/PgDn: Scroll", help(k.Preview, "Split view"), help(k.Back, "Back to note"), help(k.Quit, ""))
+}
+
+// ListHelp is shown with the notes list.
+func (k KeyMap) ListHelp() string {
+	return helpLine(help(k.NewNote, ""), help(k.NewFolder, ""), help(k.Rename, ""), help(k.Tags, ""), help(k.Journal, ""), help(k.Trash, ""),
+		help(k.Back, "Back / Return to home"), help(k.Quit, ""), help(k.Delete, ""), help(k.Open, ""))
+}
+
+// SearchHelp is shown on the search screen.
+func (k KeyMap) SearchHelp() string {
+	return helpLine("Type to search note contents (prefix*, \"exact phrase\")", "↑/↓: Select result",
+		help(k.Open, "Open at line"), help(k.Back, ""), help(k.Quit, ""))
+}
+
+// HistoryHelp is shown on the version history screen.
+func (k KeyMap) HistoryHelp() string {
+	return helpLine("↑/↓: Select version", "PgUp/PgDn: Scroll diff", help(k.Open, "Restore version"),
+		help(k.Back, "Back to note"), help(k.Quit, ""))
+}
+
+// BacklinksHelp is shown on the backlinks screen.
+func (k KeyMap) BacklinksHelp() string {
+	return helpLine("↑/↓: Select link", help(k.Open, "Open at line"), help(k.Back, "Back to note"), help(k.Quit, ""))
+}
+
+// GraphHelp is shown with the local graph.
+func (k KeyMap) GraphHelp() string {
+	return helpLine("→ links to • ← linked from • ↔ both", "↑/↓ PgUp/PgDn: Scroll", help(k.Back, "Back to note"), help(k.Quit, ""))
+}
+
+// TrashHelp is shown on the trash screen.
+func (k KeyMap) TrashHelp() string {
+	return helpLine("↑/↓: Select note", help(k.Open, "Restore note"), trashDeleteHelp(k.Delete),
+		help(k.Back, "Back to list"), help(k.Quit, ""))
+}
+
+func trashDeleteHelp(b key.Binding) string {
+	if !b.Enabled() {
+		return ""
+	}
+	return b.Help().Key + " twice: Delete permanently"
+}
+
+// TagHelp is shown while browsing tags.
+func (k KeyMap) TagHelp() string {
+	return helpLine(help(k.Open, "Show notes with tag"), "/: Filter tags", help(k.Back, "Back to list"), help(k.Quit, ""))
+}
+
+// ConflictHelp is shown when the open note changed on disk. The prompt's
+// own keys are fixed.
+func (k KeyMap) ConflictHelp() string {
+	return helpLine("r: Reload from disk", "o: Overwrite with your version", "c: Save your version as a copy", help(k.Quit, ""))
+}
+
+// LockHelp is shown when the note is open in another Totion instance.
+func (k KeyMap) LockHelp() string {
+	return helpLine("r: Open read-only", "t: Take over the lock", help(k.Back, "Close note"), help(k.Quit, ""))
+}
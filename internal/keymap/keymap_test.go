@@ -0,0 +1,141 @@
+package keymap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNew_Defaults(t *testing.T) {
+	k, err := New(nil)
+	if err != nil {
+		t.Fatalf("Expected the defaults to be valid, got %v", err)
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyCtrlN}, k.NewNote) {
+		t.Error("Expected Ctrl+N to create a note")
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyBackspace}, k.Delete) {
+		t.Error("Expected Backspace to delete")
+	}
+	want := "Ctrl+N: New Note • Ctrl+L: List all Notes • Alt+K: Search • Alt+T: Today's Note • Esc: Return to home • Ctrl+C: Quit Totion"
+	if got := k.GeneralHelp(); got != want {
+		t.Errorf("Expected help %q, got %q", want, got)
+	}
+}
+
+func TestNew_Overrides(t *testing.T) {
+	k, err := New(map[string][]string{
+		"new_note":            {"alt+n"},
+		"list":                {"ctrl+q", "f2"},
+		"toggle_autocomplete": {},
+	})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if key.Matches(tea.KeyMsg{Type: tea.KeyCtrlN}, k.NewNote) {
+		t.Error("Expected Ctrl+N to be replaced")
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n"), Alt: true}, k.NewNote) {
+		t.Error("Expected Alt+N to create a note")
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyF2}, k.List) {
+		t.Error("Expected F2 to list notes")
+	}
+	if k.ToggleAutocomplete.Enabled() {
+		t.Error("Expected an empty list to unbind the action")
+	}
+	help := k.GeneralHelp()
+	if !strings.Contains(help, "Alt+N: New Note") || !strings.Contains(help, "Ctrl+Q / F2: List all Notes") {
+		t.Errorf("Expected help to follow the bindings, got %q", help)
+	}
+}
+
+func TestNew_TerminalClashes(t *testing.T) {
+	// The remapping the README suggests for terminals and tmux setups that
+	// take ctrl+n, ctrl+l and ctrl+t.
+	k, err := New(map[string][]string{
+		"new_note":            {"alt+n"},
+		"list":                {"alt+o"},
+		"toggle_autocomplete": {"alt+a"},
+	})
+	if err != nil {
+		t.Fatalf("Expected the suggested keys to be valid, got %v", err)
+	}
+	for _, a := range actions {
+		for _, s := range a.binding(&k).Keys() {
+			if s == "ctrl+n" || s == "ctrl+l" || s == "ctrl+t" {
+				t.Errorf("Expected %q to be left free, bound to %s", s, a.name)
+			}
+		}
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		want      string
+	}{
+		{"unknown action", map[string][]string{"launch": {"ctrl+q"}}, `unknown action "launch"`},
+		{"unknown key", map[string][]string{"save": {"ctl+s"}}, `unknown key "ctl+s"`},
+		{"printable key while typing", map[string][]string{"save": {"s"}}, "would be typed"},
+		{"conflict with default", map[string][]string{"rename": {"ctrl+n"}}, `"ctrl+n" is bound to both new_note and rename`},
+		{"conflict between overrides", map[string][]string{"list": {"alt+j"}, "search": {"alt+j"}}, `"alt+j" is bound to both list and search`},
+		{"editor key", map[string][]string{"save": {"ctrl+a"}}, `"ctrl+a" is the editor's line start key`},
+		{"quit unbound", map[string][]string{"quit": {}}, "quit must have a key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.overrides)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	// List-only actions may use plain characters.
+	if _, err := New(map[string][]string{"tags": {"t"}}); err != nil {
+		t.Errorf("Expected a plain key for a list action to be allowed, got %v", err)
+	}
+	// They and the actions the editor passes on may share its keys.
+	if _, err := New(map[string][]string{"rename": {"ctrl+a"}}); err != nil {
+		t.Errorf("Expected an editor key for a list action to be allowed, got %v", err)
+	}
+	if _, err := New(map[string][]string{"open": {"enter", "ctrl+m"}}); err != nil {
+		t.Errorf("Expected open to share the editor's newline keys, got %v", err)
+	}
+}
+
+func TestDisplayKey(t *testing.T) {
+	tests := map[string]string{
+		"ctrl+n":    "Ctrl+N",
+		"esc":       "Esc",
+		"pgdown":    "PgDn",
+		"shift+tab": "Shift+Tab",
+		"alt+enter": "Alt+Enter",
+		"ctrl++":    "Ctrl++",
+		"#":         "#",
+	}
+	for in, want := range tests {
+		if got := displayKey(in); got != want {
+			t.Errorf("displayKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDefaults_EditorKeys(t *testing.T) {
+	// The defaults leave the editor's keys alone, but for ctrl+n and ctrl+t,
+	// which Editor gives up.
+	if _, err := New(nil); err != nil {
+		t.Errorf("Expected the defaults to be valid, got %v", err)
+	}
+	k := Editor()
+	if key.Matches(tea.KeyMsg{Type: tea.KeyCtrlN}, k.LineNext) || key.Matches(tea.KeyMsg{Type: tea.KeyCtrlT}, k.TransposeCharacterBackward) {
+		t.Error("Expected ctrl+n and ctrl+t to be left to Totion")
+	}
+	if !key.Matches(tea.KeyMsg{Type: tea.KeyDown}, k.LineNext) {
+		t.Error("Expected down to still move to the next line")
+	}
+}
@@ -0,0 +1,254 @@
+package links
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Node kinds. Missing nodes are the targets of dangling links. IDs of tags
+// and missing notes carry a prefix with a ":", which note paths cannot
+// contain.
+const (
+	KindNote    = "note"
+	KindTag     = "tag"
+	KindMissing = "missing"
+)
+
+// Edge kinds.
+const (
+	EdgeLink = "link"
+	EdgeTag  = "tag"
+)
+
+// Node is a note, tag or missing note in the graph. Orphans are notes
+// without links to or from other notes.
+type Node struct {
+	ID     string `json:"id"`
+	Kind   string `json:"kind"`
+	Label  string `json:"label"`
+	Orphan bool   `json:"orphan,omitempty"`
+}
+
+// Edge is a link between two notes, or from a note to one of its tags.
+// Dangling links point to a missing node.
+type Edge struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Kind     string `json:"kind"`
+	Dangling bool   `json:"dangling,omitempty"`
+}
+
+// Graph is the graph of the links between notes.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+}
+
+// Graph builds the graph of the indexed notes, with one edge per linked
+// pair of notes. Links from a note to itself are left out. With tags set
+// every tag becomes a node linked to the notes carrying it.
+func (idx *Index) Graph(tags bool) Graph {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	resolver := idx.resolver()
+
+	g := Graph{Nodes: []Node{}, Edges: []Edge{}}
+	linked := make(map[string]bool)
+	edges := make(map[Edge]bool)
+	missing := make(map[string]string)
+	tagNames := make(map[string]bool)
+	for from, doc := range idx.docs {
+		for _, ref := range doc.Refs {
+			edge := Edge{From: from, Kind: EdgeLink}
+			if target, ok := resolver.Resolve(ref, from); ok {
+				if target == from {
+					continue
+				}
+				edge.To = target
+				linked[target] = true
+			} else {
+				edge.To = "missing:" + strings.ToLower(ref.Target)
+				edge.Dangling = true
+				if _, ok := missing[edge.To]; !ok {
+					missing[edge.To] = ref.Target
+				}
+			}
+			linked[from] = true
+			edges[edge] = true
+		}
+		if tags {
+			for _, tag := range doc.Tags {
+				edges[Edge{From: from, To: "tag:" + tag, Kind: EdgeTag}] = true
+				tagNames[tag] = true
+			}
+		}
+	}
+
+	for name, doc := range idx.docs {
+		g.Nodes = append(g.Nodes, Node{ID: name, Kind: KindNote, Label: doc.Title, Orphan: !linked[name]})
+	}
+	for id, target := range missing {
+		g.Nodes = append(g.Nodes, Node{ID: id, Kind: KindMissing, Label: target})
+	}
+	for tag := range tagNames {
+		g.Nodes = append(g.Nodes, Node{ID: "tag:" + tag, Kind: KindTag, Label: "#" + tag})
+	}
+	kindOrder := map[string]int{KindNote: 0, KindMissing: 1, KindTag: 2}
+	sort.Slice(g.Nodes, func(i, j int) bool {
+		a, b := g.Nodes[i], g.Nodes[j]
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return a.ID < b.ID
+	})
+	for e := range edges {
+		g.Edges = append(g.Edges, e)
+	}
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.From != b.From {
+			return a.From < b.From
+		}
+		if a.Kind != b.Kind {
+			return a.Kind == EdgeLink
+		}
+		return a.To < b.To
+	})
+	return g
+}
+
+// WriteJSON writes the graph as indented JSON.
+func (g Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT writes the graph in the Graphviz DOT language. Orphans are drawn
+// dashed, missing notes and dangling links in red and tags as ellipses.
+func (g Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("digraph notes {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	for _, n := range g.Nodes {
+		attrs := []string{"label=" + dotQuote(n.Label)}
+		switch {
+		case n.Kind == KindMissing:
+			attrs = append(attrs, "style=dashed", "color=red", "fontcolor=red")
+		case n.Kind == KindTag:
+			attrs = append(attrs, "shape=ellipse")
+		case n.Orphan:
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&b, "\t%s [%s];\n", dotQuote(n.ID), strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "\t%s -> %s", dotQuote(e.From), dotQuote(e.To))
+		switch {
+		case e.Dangling:
+			b.WriteString(" [style=dashed, color=red]")
+		case e.Kind == EdgeTag:
+			b.WriteString(" [style=dotted, arrowhead=none]")
+		}
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// dotQuote quotes s as a DOT string.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// Tree draws the neighbourhood of the note root as a tree, depth links
+// deep: the notes it links to (→), the notes linking to it (←) or both
+// (↔). The note a branch came from is not repeated under it, and notes
+// already in the tree are not expanded again.
+func (g Graph) Tree(root string, depth int) string {
+	type neighbour struct {
+		id      string
+		out, in bool
+	}
+	adjacent := make(map[string]map[string]*neighbour)
+	add := func(a, b string, out bool) {
+		if adjacent[a] == nil {
+			adjacent[a] = make(map[string]*neighbour)
+		}
+		n := adjacent[a][b]
+		if n == nil {
+			n = &neighbour{id: b}
+			adjacent[a][b] = n
+		}
+		if out {
+			n.out = true
+		} else {
+			n.in = true
+		}
+	}
+	for _, e := range g.Edges {
+		if e.Kind == EdgeLink {
+			add(e.From, e.To, true)
+			add(e.To, e.From, false)
+		}
+	}
+	labels := make(map[string]string)
+	for _, n := range g.Nodes {
+		if n.Kind == KindMissing {
+			labels[n.ID] = n.Label + " (missing)"
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(root + "\n")
+	if len(adjacent[root]) == 0 {
+		b.WriteString("└── no links\n")
+		return b.String()
+	}
+	seen := map[string]bool{root: true}
+	var walk func(id, parent, prefix string, level int)
+	walk = func(id, parent, prefix string, level int) {
+		neighbours := make([]*neighbour, 0, len(adjacent[id]))
+		for _, n := range adjacent[id] {
+			if n.id != parent {
+				neighbours = append(neighbours, n)
+			}
+		}
+		sort.Slice(neighbours, func(i, j int) bool { return neighbours[i].id < neighbours[j].id })
+		// Siblings are claimed before descending so each note is expanded
+		// where it is closest to the root.
+		expand := make(map[string]bool)
+		for _, n := range neighbours {
+			if !seen[n.id] {
+				seen[n.id] = true
+				expand[n.id] = true
+			}
+		}
+		for i, n := range neighbours {
+			branch, indent := "├── ", "│   "
+			if i == len(neighbours)-1 {
+				branch, indent = "└── ", "    "
+			}
+			arrow := "→"
+			if n.in && n.out {
+				arrow = "↔"
+			} else if n.in {
+				arrow = "←"
+			}
+			label := labels[n.id]
+			if label == "" {
+				label = n.id
+			}
+			b.WriteString(prefix + branch + arrow + " " + label + "\n")
+			if expand[n.id] && level < depth {
+				walk(n.id, id, prefix+indent, level+1)
+			}
+		}
+	}
+	walk(root, "", "", 1)
+	return b.String()
+}
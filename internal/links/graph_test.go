@@ -0,0 +1,158 @@
+package links
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+func testGraphIndex(t *testing.T) *Index {
+	t.Helper()
+	s := store.NewMemStore()
+	s.Write("index.md", []byte("---\ntitle: Home\n---\n[[plan]], [[Plan|again]], [[ideas]] and [[index]]"))
+	s.Write("plan.md", []byte("Back [[index]], on to [[work/todo]]. #work"))
+	s.MakeDir("work")
+	s.Write("work/todo.md", []byte("#work #urgent"))
+	s.Write("lonely.md", []byte("Nobody links here."))
+	idx := NewIndex()
+	if err := idx.Refresh(context.Background(), s); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	return idx
+}
+
+func TestGraph(t *testing.T) {
+	idx := testGraphIndex(t)
+
+	g := idx.Graph(false)
+	wantNodes := []Node{
+		{ID: "index.md", Kind: KindNote, Label: "Home"},
+		{ID: "lonely.md", Kind: KindNote, Label: "lonely", Orphan: true},
+		{ID: "plan.md", Kind: KindNote, Label: "plan"},
+		{ID: "work/todo.md", Kind: KindNote, Label: "todo"},
+		{ID: "missing:ideas", Kind: KindMissing, Label: "ideas"},
+	}
+	if len(g.Nodes) != len(wantNodes) {
+		t.Fatalf("Expected %d nodes, got %+v", len(wantNodes), g.Nodes)
+	}
+	for i, n := range wantNodes {
+		if g.Nodes[i] != n {
+			t.Errorf("Node %d = %+v, want %+v", i, g.Nodes[i], n)
+		}
+	}
+	wantEdges := []Edge{
+		{From: "index.md", To: "missing:ideas", Kind: EdgeLink, Dangling: true},
+		{From: "index.md", To: "plan.md", Kind: EdgeLink},
+		{From: "plan.md", To: "index.md", Kind: EdgeLink},
+		{From: "plan.md", To: "work/todo.md", Kind: EdgeLink},
+	}
+	if len(g.Edges) != len(wantEdges) {
+		t.Fatalf("Expected %d edges, got %+v", len(wantEdges), g.Edges)
+	}
+	for i, e := range wantEdges {
+		if g.Edges[i] != e {
+			t.Errorf("Edge %d = %+v, want %+v", i, g.Edges[i], e)
+		}
+	}
+
+	withTags := idx.Graph(true)
+	var tagEdges []string
+	for _, e := range withTags.Edges {
+		if e.Kind == EdgeTag {
+			tagEdges = append(tagEdges, e.From+">"+e.To)
+		}
+	}
+	if got := strings.Join(tagEdges, ","); got != "plan.md>tag:work,work/todo.md>tag:urgent,work/todo.md>tag:work" {
+		t.Errorf("Unexpected tag edges %s", got)
+	}
+	if last := withTags.Nodes[len(withTags.Nodes)-1]; last != (Node{ID: "tag:work", Kind: KindTag, Label: "#work"}) {
+		t.Errorf("Expected tag nodes last, got %+v", last)
+	}
+}
+
+func TestGraph_Write(t *testing.T) {
+	g := testGraphIndex(t).Graph(true)
+
+	var dot bytes.Buffer
+	if err := g.WriteDOT(&dot); err != nil {
+		t.Fatalf("WriteDOT failed: %v", err)
+	}
+	for _, want := range []string{
+		"digraph notes {",
+		`"lonely.md" [label="lonely", style=dashed];`,
+		`"missing:ideas" [label="ideas", style=dashed, color=red, fontcolor=red];`,
+		`"tag:urgent" [label="#urgent", shape=ellipse];`,
+		`"index.md" -> "missing:ideas" [style=dashed, color=red];`,
+		`"index.md" -> "plan.md";`,
+		`"plan.md" -> "tag:work" [style=dotted, arrowhead=none];`,
+	} {
+		if !strings.Contains(dot.String(), want) {
+			t.Errorf("Expected DOT output to contain %s, got:\n%s", want, dot.String())
+		}
+	}
+
+	var out bytes.Buffer
+	if err := g.WriteJSON(&out); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	var decoded Graph
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if len(decoded.Nodes) != len(g.Nodes) || len(decoded.Edges) != len(g.Edges) {
+		t.Errorf("Expected the JSON to round-trip, got %+v", decoded)
+	}
+	if !strings.Contains(out.String(), `"orphan": true`) || !strings.Contains(out.String(), `"dangling": true`) {
+		t.Errorf("Expected orphans and dangling links to be flagged, got:\n%s", out.String())
+	}
+
+	if got := (Graph{}).Tree("a.md", 2); got != "a.md\n└── no links\n" {
+		t.Errorf("Unexpected tree for a note without links: %q", got)
+	}
+}
+
+func TestGraph_Tree(t *testing.T) {
+	g := testGraphIndex(t).Graph(true)
+	want := `plan.md
+├── ↔ index.md
+│   └── → ideas (missing)
+└── → work/todo.md
+`
+	if got := g.Tree("plan.md", 2); got != want {
+		t.Errorf("Tree() =\n%s\nwant\n%s", got, want)
+	}
+	want = `plan.md
+├── ↔ index.md
+└── → work/todo.md
+`
+	if got := g.Tree("plan.md", 1); got != want {
+		t.Errorf("Tree() with depth 1 =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGraph_LinkByTitle(t *testing.T) {
+	s := store.NewMemStore()
+	s.Write("Q&A- plans.md", []byte("---\ntitle: 'Q&A: plans'\n---\n"))
+	s.Write("index.md", []byte("See [[Q&A: plans]]."))
+	idx := NewIndex()
+	if err := idx.Refresh(context.Background(), s); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	g := idx.Graph(false)
+	if len(g.Edges) != 1 || g.Edges[0] != (Edge{From: "index.md", To: "Q&A- plans.md", Kind: EdgeLink}) {
+		t.Errorf("Expected a link to the titled note, got %+v", g.Edges)
+	}
+	for _, n := range g.Nodes {
+		if n.Kind == KindMissing || n.Orphan {
+			t.Errorf("Expected no missing or orphan nodes, got %+v", n)
+		}
+	}
+	if got := g.Tree("index.md", 1); got != "index.md\n└── → Q&A- plans.md\n" {
+		t.Errorf("Unexpected tree %q", got)
+	}
+}
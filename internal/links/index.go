@@ -0,0 +1,156 @@
+// Package links keeps track of the links between notes, so the notes
+// linking to a note can be listed without reading every note again.
+package links
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/AbhaySingh002/Totion/internal/file"
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+// Backlink is a link to a note from another note.
+type Backlink struct {
+	From string
+	file.Reference
+}
+
+// Doc holds the links found in a note, along with its title and tags for
+// the graph, the names links can use besides its file name, and what was
+// read to find them.
+type Doc struct {
+	ModTime int64
+	Size    int64
+	Refs    []file.Reference
+	Title   string
+	Names   []string
+	Tags    []string
+}
+
+// Index holds the links of every note in memory. Refresh brings it in line
+// with the store by comparing mtimes, and Update records a note right after
+// it was saved. It is safe for concurrent use.
+type Index struct {
+	mu   sync.RWMutex
+	docs map[string]Doc
+}
+
+func NewIndex() *Index {
+	return &Index{docs: make(map[string]Doc)}
+}
+
+// Refresh re-reads the notes whose mtime or size changed and drops deleted
+// notes.
+func (idx *Index) Refresh(ctx context.Context, s store.NoteStore) error {
+	current := make(map[string]bool)
+	err := file.WalkNotes(s, func(name string, info store.Info) error {
+		current[name] = true
+		idx.mu.RLock()
+		doc, ok := idx.docs[name]
+		idx.mu.RUnlock()
+		if ok && doc.ModTime == info.ModTime.UnixNano() && doc.Size == info.Size {
+			return ctx.Err()
+		}
+		// Notes that vanished or cannot be read are left for the next
+		// refresh to pick up.
+		if content, err := s.Read(name); err == nil {
+			idx.Update(name, content, info)
+		}
+		return ctx.Err()
+	})
+	if err != nil {
+		return err
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for name := range idx.docs {
+		if !current[name] {
+			delete(idx.docs, name)
+		}
+	}
+	return nil
+}
+
+// Update records the links of a single note, e.g. right after it was saved.
+func (idx *Index) Update(name string, content []byte, info store.Info) {
+	note := file.ParseNote(name, info.ModTime, content)
+	doc := Doc{
+		ModTime: info.ModTime.UnixNano(),
+		Size:    info.Size,
+		Refs:    file.References(string(content), name),
+		Title:   note.Title(),
+		Names:   append([]string{note.Meta().Title}, note.Meta().Aliases...),
+		Tags:    note.Tags(),
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.docs[name] = doc
+}
+
+// Remove drops a note from the index.
+func (idx *Index) Remove(name string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	delete(idx.docs, name)
+}
+
+// Notes returns the indexed notes in order.
+func (idx *Index) Notes() []string {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	names := make([]string, 0, len(idx.docs))
+	for name := range idx.docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Links returns the links found in the note called name.
+func (idx *Index) Links(name string) []file.Reference {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.docs[name].Refs
+}
+
+// Backlinks returns the links to the note called name from other notes,
+// ordered by note and line.
+func (idx *Index) Backlinks(name string) []Backlink {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	resolver := idx.resolver()
+	var backlinks []Backlink
+	for from, doc := range idx.docs {
+		if from == name {
+			continue
+		}
+		for _, ref := range doc.Refs {
+			if target, ok := resolver.Resolve(ref, from); ok && target == name {
+				backlinks = append(backlinks, Backlink{From: from, Reference: ref})
+			}
+		}
+	}
+	sort.SliceStable(backlinks, func(i, j int) bool {
+		if backlinks[i].From != backlinks[j].From {
+			return backlinks[i].From < backlinks[j].From
+		}
+		return backlinks[i].Line < backlinks[j].Line
+	})
+	return backlinks
+}
+
+// resolver resolves links among the indexed notes, by path, file name,
+// title or alias. The caller holds idx.mu.
+func (idx *Index) resolver() *file.Resolver {
+	names := make([]string, 0, len(idx.docs))
+	for name := range idx.docs {
+		names = append(names, name)
+	}
+	r := file.NewResolver(names)
+	for name, doc := range idx.docs {
+		r.AddNames(name, doc.Names...)
+	}
+	return r
+}
@@ -0,0 +1,90 @@
+package links
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+func froms(backlinks []Backlink) string {
+	got := make([]string, len(backlinks))
+	for i, b := range backlinks {
+		got[i] = fmt.Sprintf("%s:%d", b.From, b.Line)
+	}
+	return strings.Join(got, ",")
+}
+
+func TestIndex(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewMemStore()
+	s.Write("plan.md", []byte("# Plan\n\nSee [[ideas]]."))
+	s.Write("index.md", []byte("[[Plan]] and [[Plan|again]]\n\n[the plan](plan.md)"))
+	s.MakeDir("work")
+	s.Write("work/notes.md", []byte("Back to [[plan]].\n```\n[[plan]]\n```\n![img](../plan.md)"))
+	s.Write("work/plan.md", []byte("A different plan."))
+	s.Write("other.md", []byte("[[work/plan]] and [[missing]]"))
+
+	idx := NewIndex()
+	if err := idx.Refresh(ctx, s); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+
+	t.Run("finds wiki and markdown links", func(t *testing.T) {
+		backlinks := idx.Backlinks("plan.md")
+		if got := froms(backlinks); got != "index.md:1,index.md:1,index.md:3" {
+			t.Errorf("Expected links from index.md, got %s", got)
+		}
+		b := backlinks[1]
+		if b.Text[b.Start:b.End] != "[[Plan|again]]" {
+			t.Errorf("Expected offsets to cover the link, got %q", b.Text[b.Start:b.End])
+		}
+	})
+
+	t.Run("prefers notes in the same folder", func(t *testing.T) {
+		if got := froms(idx.Backlinks("work/plan.md")); got != "other.md:1,work/notes.md:1" {
+			t.Errorf("Expected links from other.md and work/notes.md, got %s", got)
+		}
+	})
+
+	t.Run("update replaces the links of a note", func(t *testing.T) {
+		content := []byte("No links any more.")
+		s.Write("index.md", content)
+		info, _ := s.Stat("index.md")
+		idx.Update("index.md", content, info)
+		if got := froms(idx.Backlinks("plan.md")); got != "" {
+			t.Errorf("Expected no backlinks, got %s", got)
+		}
+	})
+
+	t.Run("refresh picks up new and deleted notes", func(t *testing.T) {
+		s.Write("new.md", []byte("[[ideas]]"))
+		s.Write("ideas.md", []byte("# Ideas"))
+		s.Delete("plan.md")
+		if err := idx.Refresh(ctx, s); err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+		if got := froms(idx.Backlinks("ideas.md")); got != "new.md:1" {
+			t.Errorf("Expected a link from new.md only, got %s", got)
+		}
+		if got := idx.Links("plan.md"); got != nil {
+			t.Errorf("Expected the deleted note to be dropped, got %v", got)
+		}
+	})
+}
+
+func TestIndex_BacklinksByTitle(t *testing.T) {
+	s := store.NewMemStore()
+	s.Write("Q&A- plans.md", []byte("---\ntitle: 'Q&A: plans'\naliases: [faq]\n---\n"))
+	s.Write("index.md", []byte("[[Q&A: plans]]\n[[FAQ|questions]]"))
+
+	idx := NewIndex()
+	if err := idx.Refresh(context.Background(), s); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if got := froms(idx.Backlinks("Q&A- plans.md")); got != "index.md:1,index.md:2" {
+		t.Errorf("Expected links by title and alias, got %s", got)
+	}
+}
@@ -0,0 +1,178 @@
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+// Dir is the hidden folder in the notes store holding one lock file per
+// open note, mirroring the note paths.
+const Dir = ".locks"
+
+// DefaultStaleAfter is how long a lock lives without being refreshed. Locks
+// of processes on this host are also stale as soon as the process is gone.
+const DefaultStaleAfter = 5 * time.Minute
+
+// Owner identifies the Totion instance holding a lock.
+type Owner struct {
+	PID  int       `json:"pid"`
+	Host string    `json:"host"`
+	Time time.Time `json:"time"`
+}
+
+func (o Owner) String() string {
+	return fmt.Sprintf("pid %d on %s since %s", o.PID, o.Host, o.Time.Local().Format("2006-01-02 15:04"))
+}
+
+// LockedError is returned when another live instance holds the lock.
+type LockedError struct {
+	Note  string
+	Owner Owner
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s is open in another Totion (%s)", e.Note, e.Owner)
+}
+
+// Locker takes advisory locks on notes. The locks only keep Totion
+// instances from stepping on each other; other programs ignore them.
+type Locker struct {
+	Store      store.NoteStore
+	PID        int
+	Host       string
+	StaleAfter time.Duration
+	Now        func() time.Time
+	// Alive reports whether a process on this host is still running.
+	Alive func(pid int) bool
+}
+
+func New(s store.NoteStore) *Locker {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return &Locker{
+		Store:      s,
+		PID:        os.Getpid(),
+		Host:       host,
+		StaleAfter: DefaultStaleAfter,
+		Now:        time.Now,
+		Alive:      processAlive,
+	}
+}
+
+func lockName(note string) string {
+	return path.Join(Dir, note+".lock")
+}
+
+// Owner returns the current holder of the lock on note, if any.
+func (l *Locker) Owner(note string) (Owner, bool, error) {
+	data, err := l.Store.Read(lockName(note))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Owner{}, false, nil
+	}
+	if err != nil {
+		return Owner{}, false, err
+	}
+	var o Owner
+	if err := json.Unmarshal(data, &o); err != nil {
+		// A damaged lock file protects nothing.
+		return Owner{}, false, nil
+	}
+	return o, true, nil
+}
+
+func (l *Locker) mine(o Owner) bool {
+	return o.PID == l.PID && o.Host == l.Host
+}
+
+// Stale reports whether the lock of o can be ignored: it was not refreshed
+// within StaleAfter, or its process on this host has exited.
+func (l *Locker) Stale(o Owner) bool {
+	if l.StaleAfter > 0 && l.Now().Sub(o.Time) > l.StaleAfter {
+		return true
+	}
+	return o.Host == l.Host && l.Alive != nil && !l.Alive(o.PID)
+}
+
+// Acquire locks note for this instance. A lock held by another live
+// instance is reported as a *LockedError; stale locks are taken over.
+// Acquiring a lock already held refreshes it.
+func (l *Locker) Acquire(note string) error {
+	o, ok, err := l.Owner(note)
+	if err != nil {
+		return err
+	}
+	if ok && !l.mine(o) && !l.Stale(o) {
+		return &LockedError{Note: note, Owner: o}
+	}
+	if err := l.TakeOver(note); err != nil {
+		return err
+	}
+	// Two instances may have written at the same time; the file decides.
+	if o, ok, err := l.Owner(note); err == nil && ok && !l.mine(o) {
+		return &LockedError{Note: note, Owner: o}
+	}
+	return nil
+}
+
+// TakeOver locks note for this instance regardless of the current holder.
+func (l *Locker) TakeOver(note string) error {
+	if err := l.Store.MakeDir(path.Dir(lockName(note))); err != nil {
+		return err
+	}
+	data, err := json.Marshal(Owner{PID: l.PID, Host: l.Host, Time: l.Now()})
+	if err != nil {
+		return err
+	}
+	return l.Store.Write(lockName(note), data)
+}
+
+// Refresh renews the lock on note so it does not go stale. It fails with a
+// *LockedError when another instance has taken the lock over.
+func (l *Locker) Refresh(note string) error {
+	o, ok, err := l.Owner(note)
+	if err != nil {
+		return err
+	}
+	if ok && !l.mine(o) {
+		return &LockedError{Note: note, Owner: o}
+	}
+	return l.TakeOver(note)
+}
+
+// Release removes the lock on note if this instance holds it.
+func (l *Locker) Release(note string) error {
+	o, ok, err := l.Owner(note)
+	if err != nil || !ok || !l.mine(o) {
+		return err
+	}
+	err = l.Store.Delete(lockName(note))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// processAlive checks for a running process by sending it signal 0.
+// Windows does not support that, so there only the lock age counts.
+func processAlive(pid int) bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,86 @@
+package lock
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+// newTestLocker returns a Locker for a fake process on host "box" whose
+// other processes are alive unless listed in dead.
+func newTestLocker(s store.NoteStore, pid int, now *time.Time, dead map[int]bool) *Locker {
+	l := New(s)
+	l.PID = pid
+	l.Host = "box"
+	l.Now = func() time.Time { return *now }
+	l.Alive = func(pid int) bool { return !dead[pid] }
+	return l
+}
+
+func TestLocker(t *testing.T) {
+	s := store.NewMemStore()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	dead := map[int]bool{}
+	first := newTestLocker(s, 100, &now, dead)
+	second := newTestLocker(s, 200, &now, dead)
+
+	if err := first.Acquire("work/plan.md"); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if err := first.Acquire("work/plan.md"); err != nil {
+		t.Errorf("Expected re-acquiring our own lock to succeed, got %v", err)
+	}
+
+	err := second.Acquire("work/plan.md")
+	var locked *LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("Expected a LockedError, got %v", err)
+	}
+	if locked.Owner.PID != 100 || locked.Owner.Host != "box" || !locked.Owner.Time.Equal(now) {
+		t.Errorf("Expected owner pid 100 on box, got %+v", locked.Owner)
+	}
+
+	t.Run("take over", func(t *testing.T) {
+		if err := second.TakeOver("work/plan.md"); err != nil {
+			t.Fatalf("TakeOver failed: %v", err)
+		}
+		if err := first.Refresh("work/plan.md"); !errors.As(err, &locked) {
+			t.Errorf("Expected the first owner to notice the take over, got %v", err)
+		}
+		if err := first.Release("work/plan.md"); err != nil {
+			t.Fatalf("Release failed: %v", err)
+		}
+		if o, ok, _ := first.Owner("work/plan.md"); !ok || o.PID != 200 {
+			t.Error("Expected releasing to leave someone else's lock alone")
+		}
+		second.Release("work/plan.md")
+		if _, ok, _ := first.Owner("work/plan.md"); ok {
+			t.Error("Expected the lock to be gone after release")
+		}
+	})
+
+	t.Run("dead process", func(t *testing.T) {
+		first.Acquire("dead.md")
+		dead[100] = true
+		defer delete(dead, 100)
+		if err := second.Acquire("dead.md"); err != nil {
+			t.Errorf("Expected the lock of an exited process to be stale, got %v", err)
+		}
+	})
+
+	t.Run("old lock from another host", func(t *testing.T) {
+		remote := newTestLocker(s, 100, &now, dead)
+		remote.Host = "laptop"
+		remote.Acquire("remote.md")
+
+		if err := second.Acquire("remote.md"); err == nil {
+			t.Fatal("Expected a fresh lock from another host to hold")
+		}
+		now = now.Add(DefaultStaleAfter + time.Minute)
+		if err := second.Acquire("remote.md"); err != nil {
+			t.Errorf("Expected an old lock to be stale, got %v", err)
+		}
+	})
+}
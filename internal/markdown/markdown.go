@@ -0,0 +1,43 @@
+package markdown
+
+import (
+	"github.com/AbhaySingh002/Totion/internal/file"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// minWidth keeps rendering sane before the terminal size is known or on
+// very narrow terminals.
+const minWidth = 20
+
+// Renderer renders notes as styled terminal text: headings, lists,
+// emphasis, code blocks, links and tables. Building a glamour renderer is
+// comparatively slow, so one is kept until the width or style changes.
+type Renderer struct {
+	width int
+	style string
+	term  *glamour.TermRenderer
+}
+
+// Render returns content rendered to fit in width columns, using the
+// glamour style named by style. Front matter is left out.
+func (r *Renderer) Render(content string, width int, style string) (string, error) {
+	if width < minWidth {
+		width = minWidth
+	}
+	if r.term == nil || r.width != width || r.style != style {
+		term, err := glamour.NewTermRenderer(
+			glamour.WithStandardStyle(style),
+			glamour.WithWordWrap(width),
+			glamour.WithColorProfile(lipgloss.ColorProfile()),
+		)
+		if err != nil {
+			return "", err
+		}
+		r.term, r.width, r.style = term, width, style
+	}
+	// A broken front matter block comes back as part of the body, so it is
+	// shown rather than hidden.
+	_, body, _ := file.SplitFrontMatter([]byte(content))
+	return r.term.Render(string(body))
+}
@@ -0,0 +1,75 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const note = `---
+title: Plan
+tags: [work]
+---
+# Weekly plan
+
+Some *emphasis* and **strong** text in a paragraph that is long enough to need wrapping.
+
+- first item
+- second item
+
+` + "```go\nfunc main() {}\n```" + `
+
+See [the docs](https://example.com/docs).
+
+| Day | Task |
+|-----|------|
+| Mon | Plan |
+`
+
+func TestRender(t *testing.T) {
+	var r Renderer
+	out, err := r.Render(note, 40, "notty")
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	plain := ansi.Strip(out)
+
+	for _, want := range []string{"# Weekly plan", "• first item", "func main() {}", "https://example.com/docs", "Mon", "Plan"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("Expected output to contain %q:\n%s", want, plain)
+		}
+	}
+	if strings.Contains(plain, "tags: [work]") {
+		t.Error("Expected front matter to be left out")
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if w := lipgloss.Width(line); w > 40 {
+			t.Errorf("Expected lines to fit in 40 columns, got %d: %q", w, ansi.Strip(line))
+		}
+	}
+}
+
+func TestRender_ReusesRenderer(t *testing.T) {
+	var r Renderer
+	if _, err := r.Render("# a", 40, "dark"); err != nil {
+		t.Fatal(err)
+	}
+	first := r.term
+	r.Render("# b", 40, "dark")
+	if r.term != first {
+		t.Error("Expected the renderer to be reused for the same width and style")
+	}
+	r.Render("# b", 60, "dark")
+	if r.term == first {
+		t.Error("Expected a new renderer for a new width")
+	}
+}
+
+func TestRender_UnknownStyle(t *testing.T) {
+	var r Renderer
+	if _, err := r.Render("# a", 40, "neon"); err == nil {
+		t.Error("Expected an unknown style to be an error")
+	}
+}
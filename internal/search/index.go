@@ -0,0 +1,427 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/AbhaySingh002/Totion/internal/file"
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+// IndexFile is where the index is kept inside the notes store. The leading
+// dot keeps it out of note listings.
+const IndexFile = ".search-index"
+
+const indexVersion = 1
+
+// Pos is one occurrence of a token: its ordinal within the note, the
+// 1-based line and the byte offsets inside that line.
+type Pos struct {
+	N, Line, Start, End int32
+}
+
+// Doc records what was indexed for a note so changes can be detected.
+type Doc struct {
+	ModTime int64
+	Size    int64
+	Tokens  []string
+}
+
+// Index is an inverted index over note contents, persisted in the store
+// and kept current by comparing mtimes. It is safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]Doc
+	postings map[string]map[string][]Pos
+	dirty    bool
+}
+
+type indexData struct {
+	Version  int
+	Docs     map[string]Doc
+	Postings map[string]map[string][]Pos
+}
+
+func NewIndex() *Index {
+	return &Index{docs: make(map[string]Doc), postings: make(map[string]map[string][]Pos)}
+}
+
+// OpenIndex loads the index saved in s. A missing, outdated or corrupt
+// index yields an empty one that the next Refresh rebuilds.
+func OpenIndex(s store.NoteStore) *Index {
+	idx := NewIndex()
+	data, err := s.Read(IndexFile)
+	if err != nil {
+		return idx
+	}
+	var stored indexData
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&stored); err != nil || stored.Version != indexVersion {
+		idx.dirty = true
+		return idx
+	}
+	if stored.Docs != nil {
+		idx.docs = stored.Docs
+	}
+	if stored.Postings != nil {
+		idx.postings = stored.Postings
+	}
+	return idx
+}
+
+// Save writes the index to s if it changed since it was loaded.
+func (idx *Index) Save(s store.NoteStore) error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if !idx.dirty {
+		return nil
+	}
+	var buf bytes.Buffer
+	data := indexData{Version: indexVersion, Docs: idx.docs, Postings: idx.postings}
+	if err := gob.NewEncoder(&buf).Encode(data); err != nil {
+		return err
+	}
+	if err := s.Write(IndexFile, buf.Bytes()); err != nil {
+		return err
+	}
+	idx.dirty = false
+	return nil
+}
+
+// Len returns the number of indexed notes.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Refresh brings the index in line with the store: notes whose mtime or
+// size changed are re-read by a pool of workers and deleted notes dropped.
+func (idx *Index) Refresh(ctx context.Context, s store.NoteStore) error {
+	current := make(map[string]store.Info)
+	var stale []string
+	idx.mu.RLock()
+	err := file.WalkNotes(s, func(name string, info store.Info) error {
+		current[name] = info
+		doc, ok := idx.docs[name]
+		if !ok || doc.ModTime != info.ModTime.UnixNano() || doc.Size != info.Size {
+			stale = append(stale, name)
+		}
+		return ctx.Err()
+	})
+	var removed []string
+	for name := range idx.docs {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	idx.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	type result struct {
+		name    string
+		content []byte
+	}
+	jobs := make(chan string)
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range jobs {
+				// Notes that vanished or cannot be read are left for the
+				// next refresh to pick up.
+				content, err := s.Read(name)
+				if err != nil {
+					continue
+				}
+				results <- result{name, content}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for _, name := range stale {
+			select {
+			case jobs <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for r := range results {
+		info := current[r.name]
+		idx.Update(r.name, r.content, info)
+	}
+	for _, name := range removed {
+		idx.Remove(name)
+	}
+	return ctx.Err()
+}
+
+// Update (re)indexes a single note, e.g. right after it was saved.
+func (idx *Index) Update(name string, content []byte, info store.Info) {
+	tokens := tokenize(string(content))
+	byTerm := make(map[string][]Pos)
+	for _, t := range tokens {
+		byTerm[t.term] = append(byTerm[t.term], t.pos)
+	}
+	terms := make([]string, 0, len(byTerm))
+	for term := range byTerm {
+		terms = append(terms, term)
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(name)
+	for term, positions := range byTerm {
+		docs := idx.postings[term]
+		if docs == nil {
+			docs = make(map[string][]Pos)
+			idx.postings[term] = docs
+		}
+		docs[name] = positions
+	}
+	idx.docs[name] = Doc{ModTime: info.ModTime.UnixNano(), Size: info.Size, Tokens: terms}
+	idx.dirty = true
+}
+
+// Remove drops a note from the index.
+func (idx *Index) Remove(name string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removeLocked(name)
+}
+
+func (idx *Index) removeLocked(name string) {
+	doc, ok := idx.docs[name]
+	if !ok {
+		return
+	}
+	for _, term := range doc.Tokens {
+		delete(idx.postings[term], name)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.docs, name)
+	idx.dirty = true
+}
+
+type token struct {
+	term string
+	pos  Pos
+}
+
+// tokenize splits content into lowercase words of letters and digits.
+func tokenize(content string) []token {
+	var tokens []token
+	n := int32(0)
+	for lineNo, line := range strings.Split(content, "\n") {
+		start := -1
+		flush := func(end int) {
+			if start >= 0 {
+				tokens = append(tokens, token{
+					term: strings.ToLower(line[start:end]),
+					pos:  Pos{N: n, Line: int32(lineNo + 1), Start: int32(start), End: int32(end)},
+				})
+				n++
+				start = -1
+			}
+		}
+		for i, r := range line {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				if start < 0 {
+					start = i
+				}
+				continue
+			}
+			flush(i)
+		}
+		flush(len(line))
+	}
+	return tokens
+}
+
+// clause is one part of a query: a single term, a prefix ("term*") or a
+// quoted phrase.
+type clause struct {
+	terms  []string
+	prefix bool
+}
+
+// parseQuery splits a query into clauses. Text in double quotes is a
+// phrase and a trailing "*" makes a word match as a prefix.
+func parseQuery(query string) []clause {
+	var clauses []clause
+	parts := strings.Split(query, `"`)
+	for i, part := range parts {
+		if i%2 == 1 {
+			var terms []string
+			for _, t := range tokenize(part) {
+				terms = append(terms, t.term)
+			}
+			if len(terms) > 0 {
+				clauses = append(clauses, clause{terms: terms})
+			}
+			continue
+		}
+		for _, word := range strings.Fields(part) {
+			prefix := strings.HasSuffix(word, "*")
+			for _, t := range tokenize(strings.TrimSuffix(word, "*")) {
+				clauses = append(clauses, clause{terms: []string{t.term}, prefix: prefix})
+			}
+		}
+	}
+	return clauses
+}
+
+// hits returns the positions in each note where c matches. For phrases
+// the position spans from the first to the last word.
+func (idx *Index) hits(c clause) map[string][]Pos {
+	if c.prefix {
+		out := make(map[string][]Pos)
+		for term, docs := range idx.postings {
+			if !strings.HasPrefix(term, c.terms[0]) {
+				continue
+			}
+			for name, positions := range docs {
+				out[name] = append(out[name], positions...)
+			}
+		}
+		return out
+	}
+	first := idx.postings[c.terms[0]]
+	if len(c.terms) == 1 {
+		return first
+	}
+	out := make(map[string][]Pos)
+	for name, positions := range first {
+		for _, p := range positions {
+			end, ok := idx.phraseEnd(name, p, c.terms[1:])
+			if !ok {
+				continue
+			}
+			span := p
+			if end.Line == p.Line {
+				span.End = end.End
+			}
+			out[name] = append(out[name], span)
+		}
+	}
+	return out
+}
+
+// phraseEnd checks that rest follows the token at p and returns the
+// position of the last word.
+func (idx *Index) phraseEnd(name string, p Pos, rest []string) (Pos, bool) {
+	last := p
+	for i, term := range rest {
+		want := p.N + int32(i) + 1
+		found := false
+		for _, q := range idx.postings[term][name] {
+			if q.N == want {
+				last, found = q, true
+				break
+			}
+		}
+		if !found {
+			return Pos{}, false
+		}
+	}
+	return last, true
+}
+
+// Search returns the lines of notes matching every clause of query. Plain
+// words match whole words, "word*" matches a prefix and "a phrase" in
+// quotes matches consecutive words. Call Refresh first to pick up changes.
+func (idx *Index) Search(ctx context.Context, s store.NoteStore, query string) ([]Match, error) {
+	clauses := parseQuery(query)
+	if len(clauses) == 0 {
+		return nil, nil
+	}
+
+	idx.mu.RLock()
+	var docs map[string][]Pos
+	for i, c := range clauses {
+		found := idx.hits(c)
+		if i == 0 {
+			docs = make(map[string][]Pos, len(found))
+			for name, positions := range found {
+				docs[name] = append([]Pos(nil), positions...)
+			}
+			continue
+		}
+		for name := range docs {
+			if positions, ok := found[name]; ok {
+				docs[name] = append(docs[name], positions...)
+			} else {
+				delete(docs, name)
+			}
+		}
+	}
+	idx.mu.RUnlock()
+
+	names := make([]string, 0, len(docs))
+	for name := range docs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var matches []Match
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		content, err := s.Read(name)
+		if err != nil {
+			continue
+		}
+		lines := strings.Split(string(content), "\n")
+		matches = append(matches, lineMatches(name, lines, docs[name])...)
+		if len(matches) >= MaxResults {
+			return matches[:MaxResults], nil
+		}
+	}
+	return matches, nil
+}
+
+// lineMatches turns positions into one Match per line, highlighting the
+// earliest hit on that line.
+func lineMatches(name string, lines []string, positions []Pos) []Match {
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Line != positions[j].Line {
+			return positions[i].Line < positions[j].Line
+		}
+		return positions[i].Start < positions[j].Start
+	})
+	var matches []Match
+	for _, p := range positions {
+		if len(matches) > 0 && matches[len(matches)-1].Line == int(p.Line) {
+			continue
+		}
+		if int(p.Line) > len(lines) {
+			continue
+		}
+		text := strings.TrimRight(lines[p.Line-1], "\r")
+		m := Match{Path: name, Line: int(p.Line), Text: text, Start: int(p.Start), End: int(p.End)}
+		if m.End > len(text) || m.Start > m.End {
+			m.Start, m.End = -1, -1
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
@@ -0,0 +1,75 @@
+package search
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+// MaxResults caps the number of matches returned by a single search.
+const MaxResults = 500
+
+// Match is a line of a note containing the query.
+type Match struct {
+	Path  string
+	Line  int // 1-based
+	Text  string
+	Start int // byte offsets of the match within Text, -1 if unknown
+	End   int
+}
+
+// Title returns where the match is, as "path:line".
+func (m Match) Title() string { return fmt.Sprintf("%s:%d", m.Path, m.Line) }
+
+// Snippet shortens the matched line to about width characters around the
+// match. It is returned in three parts so the match can be highlighted;
+// when the match is unknown the whole snippet is in before.
+func Snippet(m Match, width int) (before, match, after string) {
+	if m.Start < 0 {
+		return truncate(m.Text, width), "", ""
+	}
+	before, match, after = m.Text[:m.Start], m.Text[m.Start:m.End], m.Text[m.End:]
+	// Keep about a third of the room before the match so it stays visible.
+	if lead := width / 3; len(before) > lead {
+		before = "…" + trimLeftBytes(before, len(before)-lead)
+	}
+	after = truncate(after, width-len(before)-len(match))
+	return before, match, after
+}
+
+func truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "…"
+}
+
+// trimLeftBytes drops about n bytes from the start of s without splitting
+// a UTF-8 sequence.
+func trimLeftBytes(s string, n int) string {
+	for n < len(s) && !utf8Start(s[n]) {
+		n++
+	}
+	return s[n:]
+}
+
+func utf8Start(b byte) bool { return b&0xC0 != 0x80 }
+
+// Search opens the index stored in s, brings it up to date, runs query
+// against it and saves the refreshed index.
+func Search(ctx context.Context, s store.NoteStore, query string) ([]Match, error) {
+	idx := OpenIndex(s)
+	if err := idx.Refresh(ctx, s); err != nil {
+		return nil, err
+	}
+	matches, err := idx.Search(ctx, s, query)
+	if err != nil {
+		return nil, err
+	}
+	return matches, idx.Save(s)
+}
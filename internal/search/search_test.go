@@ -0,0 +1,204 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+func titles(matches []Match) string {
+	got := make([]string, len(matches))
+	for i, m := range matches {
+		got[i] = m.Title()
+	}
+	return strings.Join(got, ",")
+}
+
+func TestSearch(t *testing.T) {
+	s := store.NewMemStore()
+	s.Write("b.md", []byte("groceries\nbuy Milk"))
+	s.Write("a.md", []byte("milk tea recipe"))
+	s.MakeDir("folder")
+	s.Write("folder/c.md", []byte("no match"))
+	s.Write("folder/d.md", []byte("\n\nmilkshake"))
+	s.Write("skip.txt", []byte("milk"))
+
+	t.Run("finds whole words across folders", func(t *testing.T) {
+		matches, err := Search(context.Background(), s, "milk")
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if got := titles(matches); got != "a.md:1,b.md:2" {
+			t.Errorf("Expected a.md:1,b.md:2, got %s", got)
+		}
+		m := matches[1]
+		if m.Text[m.Start:m.End] != "Milk" {
+			t.Errorf("Expected offsets to cover 'Milk', got '%s'", m.Text[m.Start:m.End])
+		}
+	})
+
+	t.Run("prefix query", func(t *testing.T) {
+		matches, err := Search(context.Background(), s, "milk*")
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if got := titles(matches); got != "a.md:1,b.md:2,folder/d.md:3" {
+			t.Errorf("Expected a.md:1,b.md:2,folder/d.md:3, got %s", got)
+		}
+	})
+
+	t.Run("phrase query", func(t *testing.T) {
+		matches, err := Search(context.Background(), s, `"buy milk"`)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if got := titles(matches); got != "b.md:2" {
+			t.Errorf("Expected b.md:2, got %s", got)
+		}
+		m := matches[0]
+		if m.Text[m.Start:m.End] != "buy Milk" {
+			t.Errorf("Expected the whole phrase to be highlighted, got '%s'", m.Text[m.Start:m.End])
+		}
+
+		if matches, _ := Search(context.Background(), s, `"milk buy"`); len(matches) != 0 {
+			t.Errorf("Expected words in the wrong order not to match, got %s", titles(matches))
+		}
+	})
+
+	t.Run("all clauses must match", func(t *testing.T) {
+		matches, err := Search(context.Background(), s, "milk tea")
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if got := titles(matches); got != "a.md:1" {
+			t.Errorf("Expected a.md:1, got %s", got)
+		}
+	})
+
+	t.Run("empty query", func(t *testing.T) {
+		matches, err := Search(context.Background(), s, "  ")
+		if err != nil || len(matches) != 0 {
+			t.Errorf("Expected no matches and no error, got %d, %v", len(matches), err)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := Search(ctx, s, "milk"); err == nil {
+			t.Error("Expected an error for a cancelled search")
+		}
+	})
+
+	t.Run("many notes", func(t *testing.T) {
+		big := store.NewMemStore()
+		for i := 0; i < 2000; i++ {
+			big.Write(fmt.Sprintf("n%04d.md", i), []byte(fmt.Sprintf("note %d\nneedle %d", i, i%7)))
+		}
+		matches, err := Search(context.Background(), big, `"needle 3"`)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(matches) != 286 {
+			t.Errorf("Expected 286 matches, got %d", len(matches))
+		}
+	})
+}
+
+func TestIndex(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("persists and reloads", func(t *testing.T) {
+		s := store.NewMemStore()
+		s.Write("a.md", []byte("persistent words"))
+
+		idx := OpenIndex(s)
+		if err := idx.Refresh(ctx, s); err != nil {
+			t.Fatalf("Refresh failed: %v", err)
+		}
+		if err := idx.Save(s); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+
+		loaded := OpenIndex(s)
+		if loaded.Len() != 1 {
+			t.Fatalf("Expected 1 indexed note after reload, got %d", loaded.Len())
+		}
+		matches, _ := loaded.Search(ctx, s, "persistent")
+		if len(matches) != 1 {
+			t.Errorf("Expected reloaded index to find the note, got %d matches", len(matches))
+		}
+	})
+
+	t.Run("refresh picks up changed and deleted notes", func(t *testing.T) {
+		s := store.NewMemStore()
+		s.Write("a.md", []byte("old text"))
+		s.Write("b.md", []byte("to be removed"))
+
+		idx := OpenIndex(s)
+		idx.Refresh(ctx, s)
+
+		time.Sleep(time.Millisecond)
+		s.Write("a.md", []byte("new text"))
+		s.Delete("b.md")
+		idx.Refresh(ctx, s)
+
+		if matches, _ := idx.Search(ctx, s, "old"); len(matches) != 0 {
+			t.Error("Expected old content to be gone from the index")
+		}
+		if matches, _ := idx.Search(ctx, s, "new"); len(matches) != 1 {
+			t.Error("Expected new content to be indexed")
+		}
+		if idx.Len() != 1 {
+			t.Errorf("Expected deleted note to be dropped, got %d notes", idx.Len())
+		}
+	})
+
+	t.Run("update indexes a single note", func(t *testing.T) {
+		s := store.NewMemStore()
+		idx := NewIndex()
+		s.Write("a.md", []byte("fresh"))
+		info, _ := s.Stat("a.md")
+		idx.Update("a.md", []byte("fresh"), info)
+
+		if matches, _ := idx.Search(ctx, s, "fresh"); len(matches) != 1 {
+			t.Error("Expected updated note to be searchable without a refresh")
+		}
+	})
+
+	t.Run("rebuilds on corruption", func(t *testing.T) {
+		s := store.NewMemStore()
+		s.Write("a.md", []byte("survivor"))
+		s.Write(IndexFile, []byte("not a gob stream"))
+
+		matches, err := Search(ctx, s, "survivor")
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(matches) != 1 {
+			t.Errorf("Expected corrupt index to be rebuilt, got %d matches", len(matches))
+		}
+		if OpenIndex(s).Len() != 1 {
+			t.Error("Expected the rebuilt index to be saved")
+		}
+	})
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("x", 100) + " target " + strings.Repeat("y", 100)
+	m := Match{Path: "n.md", Line: 1, Text: text, Start: 101, End: 107}
+	before, match, after := Snippet(m, 40)
+	if match != "target" {
+		t.Errorf("Expected the match on its own, got '%s'", match)
+	}
+	if !strings.HasPrefix(before, "…") || !strings.HasSuffix(after, "…") {
+		t.Errorf("Expected snippet to be shortened on both sides, got '%s' '%s'", before, after)
+	}
+	if before, match, after := Snippet(Match{Text: "no offsets", Start: -1}, 40); before != "no offsets" || match+after != "" {
+		t.Errorf("Expected the whole line without a match, got '%s' '%s' '%s'", before, match, after)
+	}
+}
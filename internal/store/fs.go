@@ -0,0 +1,162 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+)
+
+// FSStore keeps notes as plain files below a root directory.
+type FSStore struct {
+	Root string
+}
+
+func NewFSStore(root string) *FSStore {
+	return &FSStore{Root: root}
+}
+
+// path maps a store name to a file below Root. Names that would resolve
+// outside of Root, such as "../x" or absolute paths, are rejected.
+func (s *FSStore) path(op, name string) (string, error) {
+	if err := checkLocal(op, name); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.Root, filepath.FromSlash(name)), nil
+}
+
+func (s *FSStore) List(dir string) ([]Info, error) {
+	p, err := s.path("list", dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(p)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]Info, 0, len(entries))
+	for _, e := range entries {
+		fi, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, fileInfo(fi))
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+func (s *FSStore) Read(name string) ([]byte, error) {
+	p, err := s.path("read", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(p)
+}
+
+// Write replaces the note atomically: the data goes to a temp file in the
+// same directory which is synced and then renamed over the original, so a
+// failure at any step leaves the previous content untouched.
+func (s *FSStore) Write(name string, data []byte) (err error) {
+	target, err := s.path("write", name)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(target)
+	perm := os.FileMode(0644)
+	if fi, statErr := os.Stat(target); statErr == nil {
+		perm = fi.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), target); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir flushes directory metadata so a completed rename survives a crash.
+// Windows cannot open directories for syncing, so it is skipped there.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func (s *FSStore) MakeDir(name string) error {
+	p, err := s.path("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(p, 0750)
+}
+
+// Delete removes a note or an empty folder.
+func (s *FSStore) Delete(name string) error {
+	p, err := s.path("remove", name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(p)
+}
+
+func (s *FSStore) Rename(oldName, newName string) error {
+	oldPath, err := s.path("rename", oldName)
+	if err != nil {
+		return err
+	}
+	newPath, err := s.path("rename", newName)
+	if err != nil {
+		return err
+	}
+	return os.Rename(oldPath, newPath)
+}
+
+func (s *FSStore) Stat(name string) (Info, error) {
+	p, err := s.path("stat", name)
+	if err != nil {
+		return Info{}, err
+	}
+	fi, err := os.Stat(p)
+	if err != nil {
+		return Info{}, err
+	}
+	return fileInfo(fi), nil
+}
+
+func fileInfo(fi os.FileInfo) Info {
+	return Info{
+		Name:    fi.Name(),
+		IsDir:   fi.IsDir(),
+		Size:    fi.Size(),
+		ModTime: fi.ModTime(),
+	}
+}
@@ -0,0 +1,225 @@
+package store
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemStore is an in-memory NoteStore, mainly useful for tests.
+type MemStore struct {
+	mu    sync.RWMutex
+	files map[string]memFile
+	dirs  map[string]time.Time
+}
+
+type memFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+func NewMemStore() *MemStore {
+	return &MemStore{files: make(map[string]memFile), dirs: make(map[string]time.Time)}
+}
+
+// cleanName turns name into the key of a note or folder. Names outside the
+// root are rejected like FSStore does.
+func cleanName(op, name string) (string, error) {
+	if err := checkLocal(op, name); err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(path.Clean("/"+name), "/"), nil
+}
+
+func notExist(op, name string) error {
+	return &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+}
+
+func (s *MemStore) List(dir string) ([]Info, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	dir, err := cleanName("list", dir)
+	if err != nil {
+		return nil, err
+	}
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+	seen := make(map[string]Info)
+	for name, f := range s.files {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		rest := strings.TrimPrefix(name, prefix)
+		if i := strings.Index(rest, "/"); i >= 0 {
+			sub := rest[:i]
+			if _, ok := seen[sub]; !ok {
+				seen[sub] = Info{Name: sub, IsDir: true, ModTime: f.modTime}
+			}
+			continue
+		}
+		seen[rest] = Info{Name: rest, Size: int64(len(f.data)), ModTime: f.modTime}
+	}
+	for name, modTime := range s.dirs {
+		if !strings.HasPrefix(name, prefix) || name == dir {
+			continue
+		}
+		sub := strings.TrimPrefix(name, prefix)
+		if i := strings.Index(sub, "/"); i >= 0 {
+			sub = sub[:i]
+		}
+		if _, ok := seen[sub]; !ok {
+			seen[sub] = Info{Name: sub, IsDir: true, ModTime: modTime}
+		}
+	}
+	if _, ok := s.dirs[dir]; dir != "" && !ok && len(seen) == 0 {
+		return nil, notExist("readdir", dir)
+	}
+	infos := make([]Info, 0, len(seen))
+	for _, info := range seen {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+func (s *MemStore) Read(name string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	key, err := cleanName("open", name)
+	if err != nil {
+		return nil, err
+	}
+	f, ok := s.files[key]
+	if !ok {
+		return nil, notExist("open", name)
+	}
+	return append([]byte(nil), f.data...), nil
+}
+
+func (s *MemStore) Write(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name, err := cleanName("write", name)
+	if err != nil {
+		return err
+	}
+	if !s.hasParent(name) {
+		return notExist("write", name)
+	}
+	s.files[name] = memFile{data: append([]byte(nil), data...), modTime: time.Now()}
+	return nil
+}
+
+// hasParent reports whether the folder name is to be stored in exists, as
+// FSStore fails to write into a missing one. The caller must hold s.mu.
+func (s *MemStore) hasParent(name string) bool {
+	dir := path.Dir(name)
+	if dir == "." {
+		return true
+	}
+	_, ok := s.dirs[dir]
+	return ok
+}
+
+// addParents records the folders above name, as MakeDir creates them
+// all. The caller must hold s.mu.
+func (s *MemStore) addParents(name string) {
+	for dir := path.Dir(name); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if _, ok := s.dirs[dir]; !ok {
+			s.dirs[dir] = time.Now()
+		}
+	}
+}
+
+func (s *MemStore) MakeDir(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name, err := cleanName("mkdir", name)
+	if err != nil {
+		return err
+	}
+	if name != "" {
+		s.dirs[name] = time.Now()
+		s.addParents(name)
+	}
+	return nil
+}
+
+// Delete removes a note or an empty folder.
+func (s *MemStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name, err := cleanName("remove", name)
+	if err != nil {
+		return err
+	}
+	if _, ok := s.files[name]; ok {
+		delete(s.files, name)
+		return nil
+	}
+	if _, ok := s.dirs[name]; !ok {
+		return notExist("remove", name)
+	}
+	for other := range s.files {
+		if strings.HasPrefix(other, name+"/") {
+			return &fs.PathError{Op: "remove", Path: name, Err: errors.New("directory not empty")}
+		}
+	}
+	for other := range s.dirs {
+		if strings.HasPrefix(other, name+"/") {
+			return &fs.PathError{Op: "remove", Path: name, Err: errors.New("directory not empty")}
+		}
+	}
+	delete(s.dirs, name)
+	return nil
+}
+
+func (s *MemStore) Rename(oldName, newName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	oldName, err := cleanName("rename", oldName)
+	if err != nil {
+		return err
+	}
+	newName, err = cleanName("rename", newName)
+	if err != nil {
+		return err
+	}
+	f, ok := s.files[oldName]
+	if !ok {
+		return notExist("rename", oldName)
+	}
+	if !s.hasParent(newName) {
+		return notExist("rename", newName)
+	}
+	delete(s.files, oldName)
+	s.files[newName] = f
+	return nil
+}
+
+func (s *MemStore) Stat(name string) (Info, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	name, err := cleanName("stat", name)
+	if err != nil {
+		return Info{}, err
+	}
+	if f, ok := s.files[name]; ok {
+		return Info{Name: path.Base(name), Size: int64(len(f.data)), ModTime: f.modTime}, nil
+	}
+	if modTime, ok := s.dirs[name]; ok {
+		return Info{Name: path.Base(name), IsDir: true, ModTime: modTime}, nil
+	}
+	for other, f := range s.files {
+		if strings.HasPrefix(other, name+"/") {
+			return Info{Name: path.Base(name), IsDir: true, ModTime: f.modTime}, nil
+		}
+	}
+	return Info{}, notExist("stat", name)
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// ErrOutsideRoot is returned for names that would leave the root of a store.
+var ErrOutsideRoot = errors.New("path outside the notes directory")
+
+// checkLocal rejects names that would resolve outside of the root of a
+// store, such as "../x" or absolute paths. Every backend checks names the
+// same way so tests on one catch what the other would refuse.
+func checkLocal(op, name string) error {
+	if name != "" && !filepath.IsLocal(filepath.FromSlash(name)) {
+		return &fs.PathError{Op: op, Path: name, Err: ErrOutsideRoot}
+	}
+	return nil
+}
+
+// Info describes a single entry in a NoteStore.
+type Info struct {
+	Name    string
+	IsDir   bool
+	Size    int64
+	ModTime time.Time
+}
+
+// NoteStore is the storage backend used for notes. Names are slash
+// separated paths relative to the root of the store, e.g. "ideas.md".
+// Missing entries are reported with errors matching fs.ErrNotExist.
+type NoteStore interface {
+	List(dir string) ([]Info, error)
+	Read(name string) ([]byte, error)
+	Write(name string, data []byte) error
+	Delete(name string) error
+	Rename(oldName, newName string) error
+	Stat(name string) (Info, error)
+	MakeDir(name string) error
+}
+
+// Exists reports whether name is present in the store.
+func Exists(s NoteStore, name string) (bool, error) {
+	_, err := s.Stat(name)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
@@ -0,0 +1,232 @@
+package store
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func testStores(t *testing.T) map[string]NoteStore {
+	tmpDir, err := os.MkdirTemp("", "totion-store-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmpDir) })
+
+	return map[string]NoteStore{
+		"fs":     NewFSStore(tmpDir),
+		"memory": NewMemStore(),
+	}
+}
+
+func TestNoteStore(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := s.Write("note.md", []byte("hello")); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+
+			data, err := s.Read("note.md")
+			if err != nil {
+				t.Fatalf("Read failed: %v", err)
+			}
+			if string(data) != "hello" {
+				t.Errorf("Expected content 'hello', got '%s'", string(data))
+			}
+
+			info, err := s.Stat("note.md")
+			if err != nil {
+				t.Fatalf("Stat failed: %v", err)
+			}
+			if info.Name != "note.md" || info.IsDir || info.Size != 5 {
+				t.Errorf("Unexpected info: %+v", info)
+			}
+
+			if err := s.Rename("note.md", "renamed.md"); err != nil {
+				t.Fatalf("Rename failed: %v", err)
+			}
+			if ok, _ := Exists(s, "note.md"); ok {
+				t.Error("Expected old name to be gone after rename")
+			}
+
+			entries, err := s.List("")
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			if len(entries) != 1 || entries[0].Name != "renamed.md" {
+				t.Errorf("Expected only renamed.md, got %+v", entries)
+			}
+
+			if err := s.Delete("renamed.md"); err != nil {
+				t.Fatalf("Delete failed: %v", err)
+			}
+			if _, err := s.Read("renamed.md"); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("Expected fs.ErrNotExist after delete, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMemStore_List(t *testing.T) {
+	s := NewMemStore()
+	s.Write("a.md", []byte("a"))
+	s.MakeDir("folder")
+	s.Write("folder/b.md", []byte("b"))
+
+	entries, err := s.List("")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+	if entries[0].Name != "a.md" || entries[1].Name != "folder" || !entries[1].IsDir {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+
+	if _, err := s.List("missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist for missing dir, got %v", err)
+	}
+}
+
+func TestFSStore_AtomicWrite(t *testing.T) {
+	t.Run("replaces content without leaving temp files", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		s := NewFSStore(tmpDir)
+		s.Write("note.md", []byte("first"))
+
+		if err := s.Write("note.md", []byte("second")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+
+		data, _ := s.Read("note.md")
+		if string(data) != "second" {
+			t.Errorf("Expected content 'second', got '%s'", string(data))
+		}
+
+		entries, _ := os.ReadDir(tmpDir)
+		if len(entries) != 1 {
+			t.Errorf("Expected only note.md in the directory, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("keeps permissions of the existing file", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		s := NewFSStore(tmpDir)
+		s.Write("note.md", []byte("first"))
+		os.Chmod(filepath.Join(tmpDir, "note.md"), 0600)
+
+		s.Write("note.md", []byte("second"))
+
+		fi, err := os.Stat(filepath.Join(tmpDir, "note.md"))
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		if runtime.GOOS != "windows" && fi.Mode().Perm() != 0600 {
+			t.Errorf("Expected mode 0600, got %v", fi.Mode().Perm())
+		}
+	})
+
+	t.Run("failed rename cleans up and keeps the original", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		s := NewFSStore(tmpDir)
+		// A non-empty directory cannot be replaced by a file rename.
+		os.MkdirAll(filepath.Join(tmpDir, "blocked.md", "child"), 0755)
+
+		if err := s.Write("blocked.md", []byte("data")); err == nil {
+			t.Fatal("Expected Write over a directory to fail")
+		}
+
+		entries, _ := os.ReadDir(tmpDir)
+		if len(entries) != 1 || entries[0].Name() != "blocked.md" || !entries[0].IsDir() {
+			t.Errorf("Expected the original entry to be untouched and no temp files, got %v", entries)
+		}
+	})
+
+	t.Run("missing directory reports an error", func(t *testing.T) {
+		s := NewFSStore(filepath.Join(t.TempDir(), "missing"))
+		if err := s.Write("note.md", []byte("data")); err == nil {
+			t.Error("Expected Write into a missing directory to fail")
+		}
+	})
+}
+
+func TestFSStore_OutsideRoot(t *testing.T) {
+	tmpDir := t.TempDir()
+	s := NewFSStore(filepath.Join(tmpDir, "notes"))
+	s.MakeDir("")
+
+	for _, name := range []string{"../escape.md", "a/../../escape.md", "/etc/escape.md"} {
+		if err := s.Write(name, []byte("x")); !errors.Is(err, ErrOutsideRoot) {
+			t.Errorf("Write(%q): expected ErrOutsideRoot, got %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "escape.md")); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be written outside the root")
+	}
+	if err := s.Rename("note.md", "../note.md"); !errors.Is(err, ErrOutsideRoot) {
+		t.Errorf("Rename: expected ErrOutsideRoot, got %v", err)
+	}
+}
+
+func TestNoteStore_OutsideRoot(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := s.Write("note.md", []byte("x")); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			for _, p := range []string{"../x.md", "a/../../x.md", "/etc/x.md"} {
+				if err := s.Write(p, []byte("x")); !errors.Is(err, ErrOutsideRoot) {
+					t.Errorf("Write(%q): expected ErrOutsideRoot, got %v", p, err)
+				}
+				if _, err := s.Read(p); !errors.Is(err, ErrOutsideRoot) {
+					t.Errorf("Read(%q): expected ErrOutsideRoot, got %v", p, err)
+				}
+				if _, err := s.Stat(p); !errors.Is(err, ErrOutsideRoot) {
+					t.Errorf("Stat(%q): expected ErrOutsideRoot, got %v", p, err)
+				}
+				if err := s.Delete(p); !errors.Is(err, ErrOutsideRoot) {
+					t.Errorf("Delete(%q): expected ErrOutsideRoot, got %v", p, err)
+				}
+				if err := s.MakeDir(p); !errors.Is(err, ErrOutsideRoot) {
+					t.Errorf("MakeDir(%q): expected ErrOutsideRoot, got %v", p, err)
+				}
+				if _, err := s.List(p); !errors.Is(err, ErrOutsideRoot) {
+					t.Errorf("List(%q): expected ErrOutsideRoot, got %v", p, err)
+				}
+				if err := s.Rename("note.md", p); !errors.Is(err, ErrOutsideRoot) {
+					t.Errorf("Rename to %q: expected ErrOutsideRoot, got %v", p, err)
+				}
+			}
+			// The note that was not moved is still there under its own name.
+			if _, err := s.Read("note.md"); err != nil {
+				t.Errorf("Expected note.md to be left alone, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNoteStore_MissingFolder(t *testing.T) {
+	for name, s := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			if err := s.Write("work/plan.md", []byte("x")); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("Write: expected ErrNotExist for a missing folder, got %v", err)
+			}
+			if err := s.Write("plan.md", []byte("x")); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			if err := s.Rename("plan.md", "work/plan.md"); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("Rename: expected ErrNotExist for a missing folder, got %v", err)
+			}
+			if err := s.MakeDir("work"); err != nil {
+				t.Fatalf("MakeDir failed: %v", err)
+			}
+			if err := s.Rename("plan.md", "work/plan.md"); err != nil {
+				t.Errorf("Expected the rename to work once the folder exists, got %v", err)
+			}
+		})
+	}
+}
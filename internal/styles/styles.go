@@ -1,33 +1,95 @@
 package styles
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
 
 var (
 	WelcomeStyle = lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("16")).
-			Background(lipgloss.Color("219")).
 			Margin(2, 0, 0, 2).
 			Padding(0, 1)
 
 	TotionLogostyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#ffd505ff")).
 			Bold(true).
 			Align(lipgloss.Center).
 			Margin(2,0).
 			Width(80)
 
-	CursorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#ffd505ff"))
+	CursorStyle = lipgloss.NewStyle()
+
+	HighlightStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+
+	ErrorStyle = lipgloss.NewStyle().Bold(true)
+
+	DiffInsertStyle = lipgloss.NewStyle()
+
+	DiffDeleteStyle = lipgloss.NewStyle()
+
+	SuggestionStyle = lipgloss.NewStyle().Italic(true)
 
 	DocStyle = lipgloss.NewStyle().Margin(1, 2)
 
-	ListTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("16")).Background(lipgloss.Color("#ffd505ff")).Padding(0, 1).Margin(1, 1)
+	// PaneStyle separates the preview from the editor in the split layout.
+	PaneStyle = lipgloss.NewStyle().
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			MarginLeft(1).
+			PaddingLeft(1)
+
+	ListTitleStyle = lipgloss.NewStyle().Bold(true).Padding(0, 1).Margin(1, 1)
 
 	DescriptionStyle = lipgloss.NewStyle().
 				Bold(true).
-				Foreground(lipgloss.Color("#888")).
 				Italic(true).
 				Align(lipgloss.Center).
 				Width(80).
 				Margin(0, 0, 1, 0)
 )
+
+// current is the theme last applied.
+var current Theme
+
+func init() {
+	Apply(Themes["dark"])
+}
+
+func color(c string) lipgloss.TerminalColor {
+	if c == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(c)
+}
+
+// Apply recolours the styles with the colours of t. Without a title colour
+// title bars are drawn in reverse video so they still stand out.
+func Apply(t Theme) {
+	current = t
+	accent := color(t.Accent)
+	WelcomeStyle = WelcomeStyle.Foreground(color(t.TitleText)).Background(color(t.Title)).Reverse(t.Title == "")
+	ListTitleStyle = ListTitleStyle.Foreground(color(t.TitleText)).Background(color(t.ListTitle)).Reverse(t.ListTitle == "")
+	TotionLogostyle = TotionLogostyle.Foreground(accent)
+	CursorStyle = CursorStyle.Foreground(accent)
+	HighlightStyle = HighlightStyle.Foreground(accent)
+	ErrorStyle = ErrorStyle.Foreground(color(t.Error))
+	DescriptionStyle = DescriptionStyle.Foreground(color(t.Muted))
+	SuggestionStyle = SuggestionStyle.Foreground(color(t.Suggestion))
+	DiffInsertStyle = DiffInsertStyle.Foreground(color(t.Inserted))
+	DiffDeleteStyle = DiffDeleteStyle.Foreground(color(t.Deleted))
+	PaneStyle = PaneStyle.BorderForeground(color(t.Muted))
+}
+
+// ListDelegate returns the list item delegate with the selected item drawn
+// in the accent colour.
+func ListDelegate() list.DefaultDelegate {
+	d := list.NewDefaultDelegate()
+	accent := color(current.Accent)
+	d.Styles.SelectedTitle = d.Styles.SelectedTitle.Foreground(accent).BorderForeground(accent)
+	d.Styles.SelectedDesc = d.Styles.SelectedDesc.Foreground(accent).BorderForeground(accent)
+	return d
+}
+
+// MarkdownStyle returns the glamour style of the current theme.
+func MarkdownStyle() string {
+	return current.Markdown
+}
@@ -0,0 +1,199 @@
+package styles
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	glamourstyles "github.com/charmbracelet/glamour/styles"
+)
+
+// Auto picks the dark or light theme to match the terminal background.
+const Auto = "auto"
+
+// Theme assigns colours to the roles the UI draws with. Colours are
+// "#rrggbb" hex values or ANSI numbers "0" to "255"; an empty colour leaves
+// the terminal's own colour in place.
+type Theme struct {
+	// Base names the built-in theme a user theme starts from; its colours
+	// fill in the roles the user theme leaves empty.
+	Base       string `toml:"base,omitempty"`
+	Accent     string `toml:"accent,omitempty"`
+	Muted      string `toml:"muted,omitempty"`
+	Error      string `toml:"error,omitempty"`
+	Suggestion string `toml:"suggestion,omitempty"`
+	// Title is the background of the welcome title bar, ListTitle that of
+	// the list titles and TitleText the text on both.
+	Title     string `toml:"title,omitempty"`
+	ListTitle string `toml:"list_title,omitempty"`
+	TitleText string `toml:"title_text,omitempty"`
+	Inserted  string `toml:"inserted,omitempty"`
+	Deleted   string `toml:"deleted,omitempty"`
+	// Markdown names the glamour style of the markdown preview.
+	Markdown string `toml:"markdown,omitempty"`
+}
+
+// Themes are the built-in themes. "mono" uses no colours at all and is
+// also used whenever NO_COLOR is set.
+var Themes = map[string]Theme{
+	"dark": {
+		Accent:     "#ffd505",
+		Muted:      "#888888",
+		Error:      "#ff6b6b",
+		Suggestion: "#f1e588",
+		Title:      "219",
+		ListTitle:  "#ffd505",
+		TitleText:  "16",
+		Inserted:   "42",
+		Deleted:    "203",
+		Markdown:   glamourstyles.DarkStyle,
+	},
+	"light": {
+		Accent:     "#a06d00",
+		Muted:      "#6c6c6c",
+		Error:      "#c0262d",
+		Suggestion: "#8a7a2a",
+		Title:      "#8e44ad",
+		ListTitle:  "#a06d00",
+		TitleText:  "231",
+		Inserted:   "28",
+		Deleted:    "160",
+		Markdown:   glamourstyles.LightStyle,
+	},
+	"solarized": {
+		Accent:     "#b58900",
+		Muted:      "#839496",
+		Error:      "#dc322f",
+		Suggestion: "#93a1a1",
+		Title:      "#268bd2",
+		ListTitle:  "#b58900",
+		TitleText:  "#fdf6e3",
+		Inserted:   "#859900",
+		Deleted:    "#dc322f",
+		Markdown:   glamourstyles.DarkStyle,
+	},
+	"mono": {Markdown: glamourstyles.NoTTYStyle},
+}
+
+// ThemeNames lists the built-in themes in order.
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MarkdownStyles lists the glamour styles a theme can use for the preview.
+func MarkdownStyles() []string {
+	var names []string
+	for name := range glamourstyles.DefaultStyles {
+		// "auto" would query the terminal again on every render.
+		if name != glamourstyles.AutoStyle {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+var hexColorRe = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+// ValidColor reports whether c is a hex colour or an ANSI colour number.
+func ValidColor(c string) bool {
+	if hexColorRe.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// Roles returns the colours of t by role name, as used in the config file.
+func (t Theme) Roles() []struct{ Name, Color string } {
+	return []struct{ Name, Color string }{
+		{"accent", t.Accent},
+		{"muted", t.Muted},
+		{"error", t.Error},
+		{"suggestion", t.Suggestion},
+		{"title", t.Title},
+		{"list_title", t.ListTitle},
+		{"title_text", t.TitleText},
+		{"inserted", t.Inserted},
+		{"deleted", t.Deleted},
+	}
+}
+
+// Validate checks a user theme: its base must be built in and its colours
+// well-formed. Empty colours are taken from the base.
+func (t Theme) Validate() error {
+	if t.Base != "" && t.Base != Auto {
+		if _, ok := Themes[t.Base]; !ok {
+			return fmt.Errorf("base must be %q or one of %v, got %q", Auto, ThemeNames(), t.Base)
+		}
+	}
+	for _, role := range t.Roles() {
+		if role.Color != "" && !ValidColor(role.Color) {
+			return fmt.Errorf("%s must be a hex colour like \"#ffd505\" or an ANSI number, got %q", role.Name, role.Color)
+		}
+	}
+	if _, ok := glamourstyles.DefaultStyles[t.Markdown]; t.Markdown != "" && (!ok || t.Markdown == glamourstyles.AutoStyle) {
+		return fmt.Errorf("markdown must be one of %v, got %q", MarkdownStyles(), t.Markdown)
+	}
+	return nil
+}
+
+// over fills the empty roles of t from base.
+func (t Theme) over(base Theme) Theme {
+	fill := func(c *string, from string) {
+		if *c == "" {
+			*c = from
+		}
+	}
+	fill(&t.Accent, base.Accent)
+	fill(&t.Muted, base.Muted)
+	fill(&t.Error, base.Error)
+	fill(&t.Suggestion, base.Suggestion)
+	fill(&t.Title, base.Title)
+	fill(&t.ListTitle, base.ListTitle)
+	fill(&t.TitleText, base.TitleText)
+	fill(&t.Inserted, base.Inserted)
+	fill(&t.Deleted, base.Deleted)
+	fill(&t.Markdown, base.Markdown)
+	t.Base = ""
+	return t
+}
+
+// Resolve returns the theme called name, looking at the user's themes
+// before the built-in ones. "auto", and user themes without a base, follow
+// the terminal background as reported by dark, which is only asked when
+// needed since it queries the terminal. noColor, set from NO_COLOR, always
+// wins and yields the mono theme.
+func Resolve(name string, custom map[string]Theme, dark func() bool, noColor bool) (Theme, error) {
+	if noColor {
+		return Themes["mono"], nil
+	}
+	auto := func() Theme {
+		if dark() {
+			return Themes["dark"]
+		}
+		return Themes["light"]
+	}
+	if name == "" || name == Auto {
+		return auto(), nil
+	}
+	if t, ok := custom[name]; ok {
+		if err := t.Validate(); err != nil {
+			return Theme{}, fmt.Errorf("theme %q: %w", name, err)
+		}
+		if t.Base == "" || t.Base == Auto {
+			return t.over(auto()), nil
+		}
+		return t.over(Themes[t.Base]), nil
+	}
+	if t, ok := Themes[name]; ok {
+		return t, nil
+	}
+	return Theme{}, fmt.Errorf("unknown theme %q", name)
+}
@@ -0,0 +1,100 @@
+package styles
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	dark := func() bool { return true }
+	light := func() bool { return false }
+	custom := map[string]Theme{
+		"paper": {Base: "light", Accent: "#ff0000"},
+		"night": {Error: "196"},
+	}
+
+	tests := []struct {
+		name    string
+		theme   string
+		dark    func() bool
+		noColor bool
+		want    Theme
+	}{
+		{"auto on dark", Auto, dark, false, Themes["dark"]},
+		{"auto on light", Auto, light, false, Themes["light"]},
+		{"built-in", "solarized", dark, false, Themes["solarized"]},
+		{"NO_COLOR wins", "solarized", dark, true, Themes["mono"]},
+		{"user theme on its base", "paper", dark, false, func() Theme {
+			t := Themes["light"]
+			t.Accent = "#ff0000"
+			return t
+		}()},
+		{"user theme without base follows the terminal", "night", light, false, func() Theme {
+			t := Themes["light"]
+			t.Error = "196"
+			return t
+		}()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(tt.theme, custom, tt.dark, tt.noColor)
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := Resolve("neon", custom, dark, false); err == nil {
+		t.Error("Expected an unknown theme to be an error")
+	}
+}
+
+func TestResolve_OnlyQueriesTerminalWhenNeeded(t *testing.T) {
+	asked := false
+	dark := func() bool { asked = true; return true }
+	if _, err := Resolve("light", nil, dark, false); err != nil {
+		t.Fatal(err)
+	}
+	if asked {
+		t.Error("Expected an explicit theme not to query the terminal background")
+	}
+}
+
+func TestTheme_Validate(t *testing.T) {
+	if err := (Theme{Base: "dark", Accent: "#abc", Title: "219"}).Validate(); err != nil {
+		t.Errorf("Expected a valid theme, got %v", err)
+	}
+	if err := (Theme{Suggestion: "yellow"}).Validate(); err == nil || !strings.Contains(err.Error(), "suggestion") {
+		t.Errorf("Expected an error about the suggestion colour, got %v", err)
+	}
+	if err := (Theme{Base: "paper"}).Validate(); err == nil {
+		t.Error("Expected a base that is not built in to be an error")
+	}
+	for name, theme := range Themes {
+		if err := theme.Validate(); err != nil {
+			t.Errorf("Built-in theme %s is invalid: %v", name, err)
+		}
+	}
+}
+
+func TestApply_Mono(t *testing.T) {
+	defer Apply(Themes["dark"])
+	Apply(Themes["mono"])
+	if !ListTitleStyle.GetReverse() {
+		t.Error("Expected title bars in reverse video without a title colour")
+	}
+	Apply(Themes["dark"])
+	if ListTitleStyle.GetReverse() {
+		t.Error("Expected coloured title bars not to be reversed")
+	}
+	// The default look of the list titles: black on yellow.
+	if ListTitleStyle.GetBackground() != color("#ffd505") || ListTitleStyle.GetForeground() != color("16") {
+		t.Errorf("Expected the list title in black on #ffd505, got %v on %v", ListTitleStyle.GetForeground(), ListTitleStyle.GetBackground())
+	}
+	if WelcomeStyle.GetBackground() != color("219") {
+		t.Errorf("Expected the welcome title on 219, got %v", WelcomeStyle.GetBackground())
+	}
+}
@@ -0,0 +1,185 @@
+package trash
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+// Dir is the hidden folder in the notes store holding deleted notes. Each
+// entry is a "<id>.md" file with the note content and a "<id>.json" file
+// recording where it came from.
+const Dir = ".trash"
+
+// DefaultRetention is how long deleted notes are kept before PurgeExpired
+// removes them for good.
+const DefaultRetention = 30 * 24 * time.Hour
+
+const idLayout = "20060102T150405.000000000"
+
+// Entry is a note in the trash.
+type Entry struct {
+	ID      string    `json:"-"`
+	Path    string    `json:"path"`
+	Deleted time.Time `json:"deleted"`
+}
+
+// Trash moves notes out of the way instead of deleting them.
+type Trash struct {
+	Store     store.NoteStore
+	RetainFor time.Duration
+	Now       func() time.Time
+}
+
+func New(s store.NoteStore) *Trash {
+	return &Trash{Store: s, RetainFor: DefaultRetention, Now: time.Now}
+}
+
+func contentName(id string) string { return path.Join(Dir, id+".md") }
+
+func metaName(id string) string { return path.Join(Dir, id+".json") }
+
+// Move puts the note stored under name into the trash.
+func (t *Trash) Move(name string) (Entry, error) {
+	if err := t.Store.MakeDir(Dir); err != nil {
+		return Entry{}, err
+	}
+	now := t.Now()
+	e := Entry{ID: now.UTC().Format(idLayout), Path: name, Deleted: now}
+	for n := 1; ; n++ {
+		exists, err := store.Exists(t.Store, metaName(e.ID))
+		if err != nil {
+			return Entry{}, err
+		}
+		if !exists {
+			break
+		}
+		e.ID = fmt.Sprintf("%s-%d", now.UTC().Format(idLayout), n)
+	}
+	meta, err := json.Marshal(e)
+	if err != nil {
+		return Entry{}, err
+	}
+	if err := t.Store.Write(metaName(e.ID), meta); err != nil {
+		return Entry{}, err
+	}
+	if err := t.Store.Rename(name, contentName(e.ID)); err != nil {
+		t.Store.Delete(metaName(e.ID))
+		return Entry{}, err
+	}
+	return e, nil
+}
+
+// List returns the notes in the trash, most recently deleted first.
+// Entries with unreadable metadata are skipped.
+func (t *Trash) List() ([]Entry, error) {
+	infos, err := t.Store.List(Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, info := range infos {
+		if info.IsDir || !strings.HasSuffix(info.Name, ".json") {
+			continue
+		}
+		data, err := t.Store.Read(path.Join(Dir, info.Name))
+		if err != nil {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(data, &e); err != nil || e.Path == "" {
+			continue
+		}
+		e.ID = strings.TrimSuffix(info.Name, ".json")
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Deleted.After(entries[j].Deleted) })
+	return entries, nil
+}
+
+// Read returns the content of a trashed note.
+func (t *Trash) Read(e Entry) ([]byte, error) {
+	return t.Store.Read(contentName(e.ID))
+}
+
+// Restore moves a trashed note back to its original path and returns the
+// path it was restored to. When another note has taken that path since, it
+// is restored next to it as "name (restored).md".
+func (t *Trash) Restore(e Entry) (string, error) {
+	target, err := t.freeName(e.Path)
+	if err != nil {
+		return "", err
+	}
+	if dir := path.Dir(target); dir != "." {
+		if err := t.Store.MakeDir(dir); err != nil {
+			return "", err
+		}
+	}
+	if err := t.Store.Rename(contentName(e.ID), target); err != nil {
+		return "", err
+	}
+	t.Store.Delete(metaName(e.ID))
+	return target, nil
+}
+
+func (t *Trash) freeName(name string) (string, error) {
+	base := strings.TrimSuffix(name, ".md")
+	candidate := name
+	for n := 1; ; n++ {
+		exists, err := store.Exists(t.Store, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		if n == 1 {
+			candidate = base + " (restored).md"
+		} else {
+			candidate = fmt.Sprintf("%s (restored %d).md", base, n)
+		}
+	}
+}
+
+// Purge deletes a trashed note permanently.
+func (t *Trash) Purge(e Entry) error {
+	if err := t.Store.Delete(contentName(e.ID)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return t.Store.Delete(metaName(e.ID))
+}
+
+// PurgeExpired permanently deletes notes that have been in the trash for
+// longer than RetainFor and reports how many were removed. A zero RetainFor
+// keeps notes forever.
+func (t *Trash) PurgeExpired() (int, error) {
+	if t.RetainFor <= 0 {
+		return 0, nil
+	}
+	entries, err := t.List()
+	if err != nil {
+		return 0, err
+	}
+	now := t.Now()
+	purged := 0
+	for _, e := range entries {
+		if now.Sub(e.Deleted) <= t.RetainFor {
+			continue
+		}
+		if err := t.Purge(e); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
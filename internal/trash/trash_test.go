@@ -0,0 +1,114 @@
+package trash
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AbhaySingh002/Totion/internal/store"
+)
+
+func newTestTrash(t *testing.T) (*Trash, *store.MemStore, *time.Time) {
+	t.Helper()
+	s := store.NewMemStore()
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	tr := New(s)
+	tr.Now = func() time.Time { return now }
+	return tr, s, &now
+}
+
+func TestTrash_MoveAndRestore(t *testing.T) {
+	tr, s, _ := newTestTrash(t)
+	s.MakeDir("work")
+	s.Write("work/plan.md", []byte("plan"))
+
+	e, err := tr.Move("work/plan.md")
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if exists, _ := store.Exists(s, "work/plan.md"); exists {
+		t.Error("Expected note to be gone from its folder")
+	}
+
+	entries, err := tr.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Path != "work/plan.md" || entries[0].ID != e.ID {
+		t.Fatalf("Expected the trashed note with its original path, got %+v", entries)
+	}
+	if content, _ := tr.Read(entries[0]); string(content) != "plan" {
+		t.Errorf("Expected trashed content 'plan', got '%s'", string(content))
+	}
+
+	t.Run("restore to original path", func(t *testing.T) {
+		restored, err := tr.Restore(entries[0])
+		if err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		if restored != "work/plan.md" {
+			t.Errorf("Expected restore to 'work/plan.md', got '%s'", restored)
+		}
+		if content, _ := s.Read("work/plan.md"); string(content) != "plan" {
+			t.Errorf("Expected restored content 'plan', got '%s'", string(content))
+		}
+		if entries, _ := tr.List(); len(entries) != 0 {
+			t.Errorf("Expected empty trash after restore, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("restore next to a newer note", func(t *testing.T) {
+		e, _ := tr.Move("work/plan.md")
+		s.Write("work/plan.md", []byte("new plan"))
+
+		restored, err := tr.Restore(e)
+		if err != nil {
+			t.Fatalf("Restore failed: %v", err)
+		}
+		if restored != "work/plan (restored).md" {
+			t.Errorf("Expected restore to 'work/plan (restored).md', got '%s'", restored)
+		}
+		if content, _ := s.Read("work/plan.md"); string(content) != "new plan" {
+			t.Error("Expected the newer note to be left alone")
+		}
+	})
+}
+
+func TestTrash_Purge(t *testing.T) {
+	tr, s, now := newTestTrash(t)
+	tr.RetainFor = 7 * 24 * time.Hour
+
+	s.Write("old.md", []byte("old"))
+	s.Write("recent.md", []byte("recent"))
+	s.Write("gone.md", []byte("gone"))
+
+	tr.Move("old.md")
+	*now = now.Add(5 * 24 * time.Hour)
+	tr.Move("recent.md")
+	gone, _ := tr.Move("gone.md")
+
+	if err := tr.Purge(gone); err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if entries, _ := tr.List(); len(entries) != 2 {
+		t.Fatalf("Expected 2 entries after purge, got %d", len(entries))
+	}
+
+	*now = now.Add(3 * 24 * time.Hour)
+	purged, err := tr.PurgeExpired()
+	if err != nil {
+		t.Fatalf("PurgeExpired failed: %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("Expected 1 expired note purged, got %d", purged)
+	}
+	entries, _ := tr.List()
+	if len(entries) != 1 || entries[0].Path != "recent.md" {
+		t.Errorf("Expected only 'recent.md' to remain, got %+v", entries)
+	}
+
+	tr.RetainFor = 0
+	*now = now.Add(365 * 24 * time.Hour)
+	if purged, _ := tr.PurgeExpired(); purged != 0 {
+		t.Errorf("Expected zero retention to keep notes forever, purged %d", purged)
+	}
+}
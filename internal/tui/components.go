@@ -1,17 +1,19 @@
 package tui
 
 import (
+	"github.com/AbhaySingh002/Totion/internal/config"
+	"github.com/AbhaySingh002/Totion/internal/keymap"
 	"github.com/AbhaySingh002/Totion/internal/styles"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 )
 
-func NewTextInput() textinput.Model {
+func NewTextInput(cfg config.Editor) textinput.Model {
 	ti := textinput.New()
 	ti.Placeholder = "What do you wanna name it?"
 	ti.Focus()
-	ti.CharLimit = 30
+	ti.CharLimit = cfg.NameLimit
 	ti.Width = 50
 	ti.Cursor.Style = styles.CursorStyle
 	return ti
@@ -24,5 +26,6 @@ func NewTextArea() textarea.Model {
 	nt.ShowLineNumbers = false
 	nt.Placeholder = "Type your notes...."
 	nt.Cursor.Style = styles.CursorStyle
+	nt.KeyMap = keymap.Editor()
 	return nt
 }
\ No newline at end of file
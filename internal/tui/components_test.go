@@ -3,12 +3,14 @@ package tui
 import (
 	"testing"
 
+	"github.com/AbhaySingh002/Totion/internal/config"
+
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 )
 
 func TestNewTextInput(t *testing.T) {
-	ti := NewTextInput()
+	ti := NewTextInput(config.Default().Editor)
 
 	t.Run("placeholder is set", func(t *testing.T) {
 		if ti.Placeholder != "What do you wanna name it?" {